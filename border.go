@@ -0,0 +1,81 @@
+package pprint
+
+import "strings"
+
+// Strings to draw table borders with. See WithBorderStyle().
+type BorderStyle struct {
+	// Rules and column separators.
+	Horizontal, Vertical string
+
+	// Joints of the rule above the table.
+	TopLeft, TopMid, TopRight string
+
+	// Joints of the rules below the header and between rows of different schemas.
+	MidLeft, Mid, MidRight string
+
+	// Joints of the rule below the table.
+	BottomLeft, BottomMid, BottomRight string
+}
+
+var (
+	// Borders drawn with "+-|", looks like mysql tables.
+	BorderASCII = BorderStyle{
+		Horizontal: "-", Vertical: "|",
+		TopLeft: "+", TopMid: "+", TopRight: "+",
+		MidLeft: "+", Mid: "+", MidRight: "+",
+		BottomLeft: "+", BottomMid: "+", BottomRight: "+",
+	}
+
+	// Borders drawn with unicode box-drawing characters.
+	BorderUnicode = BorderStyle{
+		Horizontal: "─", Vertical: "│",
+		TopLeft: "┌", TopMid: "┬", TopRight: "┐",
+		MidLeft: "├", Mid: "┼", MidRight: "┤",
+		BottomLeft: "└", BottomMid: "┴", BottomRight: "┘",
+	}
+)
+
+type ruleKind int
+
+const (
+	ruleTop ruleKind = iota
+	ruleMid
+	ruleBottom
+)
+
+// Returns a horizontal rule that fits cols, each column is padded with a space on both sides.
+func (b *BorderStyle) rule(kind ruleKind, cols []Column) string {
+	var l, m, r string
+	switch kind {
+	case ruleTop:
+		l, m, r = b.TopLeft, b.TopMid, b.TopRight
+	case ruleMid:
+		l, m, r = b.MidLeft, b.Mid, b.MidRight
+	case ruleBottom:
+		l, m, r = b.BottomLeft, b.BottomMid, b.BottomRight
+	}
+
+	var sb strings.Builder
+	sb.WriteString(l)
+	first := true
+	for _, c := range cols {
+		if c.hidden {
+			continue
+		}
+		if !first {
+			sb.WriteString(m)
+		}
+		sb.WriteString(strings.Repeat(b.Horizontal, c.width+2))
+		first = false
+	}
+	sb.WriteString(r)
+	return sb.String()
+}
+
+// Draw table borders: rules above and below the table, below the header, between columns and
+// between rows of different schemas. It takes precedence over WithColSep().
+func WithBorderStyle(b BorderStyle) PrintingOpt {
+	return func(p *Printing) {
+		p.border = &b
+	}
+}
@@ -0,0 +1,91 @@
+package pprint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBorderStyle(t *testing.T) {
+	newNode := func() *Node {
+		n := NewNode()
+		n.Push("alice", 30)
+		n.Push("bob", 4)
+		return n
+	}
+
+	tests := map[string]struct {
+		n     *Node
+		pOpts []PrintingOpt
+		out   string
+	}{
+		"empty node draws nothing": {
+			NewNode(),
+			[]PrintingOpt{WithBorderStyle(BorderASCII)},
+			"",
+		},
+		"ascii": {
+			newNode(),
+			[]PrintingOpt{WithBorderStyle(BorderASCII)},
+			"+-------+----+\n" +
+				"| alice | 30 |\n" +
+				"|   bob |  4 |\n" +
+				"+-------+----+\n",
+		},
+		"ascii with header": {
+			newNode(),
+			[]PrintingOpt{WithBorderStyle(BorderASCII), WithHeader("NAME", "AGE")},
+			"+-------+-----+\n" +
+				"|  NAME | AGE |\n" +
+				"+-------+-----+\n" +
+				"| alice |  30 |\n" +
+				"|   bob |   4 |\n" +
+				"+-------+-----+\n",
+		},
+		"unicode": {
+			newNode(),
+			[]PrintingOpt{WithBorderStyle(BorderUnicode), WithHeader("NAME")},
+			"┌───────┬────┐\n" +
+				"│  NAME │    │\n" +
+				"├───────┼────┤\n" +
+				"│ alice │ 30 │\n" +
+				"│   bob │  4 │\n" +
+				"└───────┴────┘\n",
+		},
+		"plain wins": {
+			newNode(),
+			[]PrintingOpt{WithBorderStyle(BorderUnicode), WithPlain()},
+			"alice 30\n" +
+				"  bob  4\n",
+		},
+	}
+
+	for name, test := range tests {
+		var s strings.Builder
+		Print(test.n, append(test.pOpts, WithWriter(&s))...)
+		assert.Equal(t, test.out, s.String(), name)
+	}
+}
+
+func TestBorderStyleWithDifferentSchemas(t *testing.T) {
+	var s strings.Builder
+
+	n := NewNode()
+	m, _ := n.Push("a", "b")
+	n.Push("cc", "dd")
+	m.PushRow(NewRow(WithRowData(1, 2, 3)))
+
+	Print(n, WithWriter(&s), WithBorderStyle(BorderASCII))
+	assert.Equal(
+		t,
+		"+----+----+\n"+
+			"|  a |  b |\n"+
+			"+---+---+---+\n"+
+			"| 1 | 2 | 3 |\n"+
+			"+----+----+\n"+
+			"| cc | dd |\n"+
+			"+----+----+\n",
+		s.String(),
+	)
+}
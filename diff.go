@@ -0,0 +1,149 @@
+package pprint
+
+import (
+	"strings"
+)
+
+// Styles of the cell-level diff.
+const (
+	styleDiffOld = Style("9;31") // struck through red
+	styleDiffNew = StyleGreen
+)
+
+// Returns a row showing which cells changed from old to new. Changed cells render as "old→new",
+// or as the old value struck through in red followed by the new value in green if styled is true.
+// Unchanged cells keep the values of new. Cells are compared by their string representations.
+//
+// The returned row has its own copy of the schema of new, so the widths of new aren't affected.
+// Columns missing from either row compare as empty strings.
+func DiffRow(old, new *Row, styled bool) *Row {
+	var (
+		cols   = append([]Column(nil), new.schema.cols...)
+		fields = make([]interface{}, len(cols))
+	)
+	for i := range cols {
+		// Formatting has been done, the schema copy only keeps the layout.
+		cols[i].format, cols[i].parse, cols[i].compute = nil, nil, nil
+
+		var o, n string
+		if i < len(old.fmtArgs) {
+			o = old.fmtArgs[i].(string)
+		}
+		n = new.fmtArgs[i].(string)
+
+		switch {
+		case o == n:
+			fields[i] = n
+		case styled:
+			fields[i] = styleDiffOld.apply(o) + styleDiffNew.apply(n)
+		default:
+			fields[i] = o + "→" + n
+		}
+	}
+	return NewRow(WithRowColumns(cols...), WithRowData(fields...))
+}
+
+// Kinds of the differences between trees.
+type DiffKind int
+
+const (
+	// A node of the new tree missing from the old one, with its descendants.
+	DiffAdded DiffKind = iota
+
+	// A node of the old tree missing from the new one, with its descendants.
+	DiffRemoved
+
+	// A node whose row changed, compared by the string representations.
+	DiffChanged
+)
+
+// A difference between two trees found by Node.Diff().
+type RowDiff struct {
+	Kind DiffKind
+
+	// Indexes of the node among its siblings, from the children of the compared nodes down.
+	Path []int
+
+	// Node of the old and the new tree, Old is nil if added, New is nil if removed.
+	Old, New *Node
+}
+
+// Returns the row showing the difference: the row of the added or removed node, or DiffRow() of
+// the changed rows.
+func (d RowDiff) Row(styled bool) *Row {
+	switch d.Kind {
+	case DiffAdded:
+		return d.New.Row()
+	case DiffRemoved:
+		return d.Old.Row()
+	}
+	return DiffRow(d.Old.Row(), d.New.Row(), styled)
+}
+
+// Returns the difference in a line, e.g. "~ 0.2: alice 1.0→2.5".
+func (d RowDiff) String() string {
+	var (
+		b    strings.Builder
+		sign = map[DiffKind]string{DiffAdded: "+", DiffRemoved: "-", DiffChanged: "~"}[d.Kind]
+	)
+	b.WriteString(sign + " " + joinPath(d.Path) + ": ")
+
+	var args []string
+	if r := d.Row(false); r != nil {
+		for _, a := range r.fmtArgs {
+			args = append(args, a.(string))
+		}
+	}
+	b.WriteString(strings.Join(args, " "))
+	return b.String()
+}
+
+// Compares the descendants of receiver, the old tree, with the ones of other, the new tree, and
+// returns the differences in the order of Walk(). Children are matched by their positions among
+// their siblings, rows are compared by their string representations. The descendants of added or
+// removed nodes aren't reported separately.
+func (n *Node) Diff(other *Node) []RowDiff {
+	var (
+		out  []RowDiff
+		walk func(o, m *Node, path []int)
+	)
+	walk = func(o, m *Node, path []int) {
+		for i := 0; i < len(o.nodes) || i < len(m.nodes); i++ {
+			p := append(append([]int(nil), path...), i)
+			switch {
+			case i >= len(m.nodes):
+				out = append(out, RowDiff{Kind: DiffRemoved, Path: p, Old: o.nodes[i]})
+			case i >= len(o.nodes):
+				out = append(out, RowDiff{Kind: DiffAdded, Path: p, New: m.nodes[i]})
+			default:
+				if !sameRow(o.nodes[i].row, m.nodes[i].row) {
+					out = append(out, RowDiff{Kind: DiffChanged, Path: p, Old: o.nodes[i], New: m.nodes[i]})
+				}
+				walk(o.nodes[i], m.nodes[i], p)
+			}
+		}
+	}
+	walk(n, other, nil)
+	return out
+}
+
+// Returns true if the descendants of receiver and other have the same structure and rows, see Diff().
+func (n *Node) Equal(other *Node) bool {
+	return len(n.Diff(other)) == 0
+}
+
+// Returns true if the string representations of a and b are equal.
+func sameRow(a, b *Row) bool {
+	switch {
+	case a == nil || b == nil:
+		return a == b
+	case len(a.fmtArgs) != len(b.fmtArgs):
+		return false
+	}
+	for i := range a.fmtArgs {
+		if a.fmtArgs[i] != b.fmtArgs[i] {
+			return false
+		}
+	}
+	return true
+}
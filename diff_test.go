@@ -0,0 +1,75 @@
+package pprint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffRow(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewSchema(NewColumn(), NewColumn(WithVerb("%.1f")), NewColumn())
+	old := NewRow(WithRowSchema(s), WithRowData("alice", 1.0, "ok"))
+	new := NewRow(WithRowSchema(s), WithRowData("alice", 2.5, "failed"))
+
+	{
+		d := DiffRow(old, new, false)
+		assert.Equal([]interface{}{"alice", "1.0→2.5", "ok→failed"}, d.FmtArgs())
+		assert.NotSame(s, d.Schema())
+		assert.Equal("%6s", s.cols[2].String(), "schema of new is untouched")
+	}
+	{
+		d := DiffRow(old, new, true)
+		assert.Equal([]interface{}{
+			"alice",
+			"\x1b[9;31m1.0\x1b[0m\x1b[32m2.5\x1b[0m",
+			"\x1b[9;31mok\x1b[0m\x1b[32mfailed\x1b[0m",
+		}, d.FmtArgs())
+		assert.Equal("alice 1.02.5 okfailed", stripANSI(d.String()))
+	}
+	{
+		d := DiffRow(NewRow(WithRowData("alice")), new, false)
+		assert.Equal([]interface{}{"alice", "→2.5", "→failed"}, d.FmtArgs(), "missing columns")
+	}
+}
+
+func TestNodeDiff(t *testing.T) {
+	assert := assert.New(t)
+
+	newTree := func(score float64, extra bool) *Node {
+		n := NewNode(WithColumns(NewColumn(), NewColumn(WithVerb("%.1f"))))
+		a, _ := n.Push("alice", 1.0)
+		a.Push("task", score)
+		b, _ := n.Push("bob", 2.0)
+		b.Push("task", 1.0)
+		if extra {
+			n.Push("carol", 3.0)
+		}
+		return n
+	}
+
+	old := newTree(1.0, true)
+	assert.True(old.Equal(newTree(1.0, true)))
+	assert.Empty(old.Diff(old))
+
+	new := newTree(2.5, false)
+	new.nodes[1].Push("review", 0.5)
+	assert.False(old.Equal(new))
+
+	diffs := old.Diff(new)
+	if assert.Len(diffs, 3) {
+		assert.Equal(DiffChanged, diffs[0].Kind)
+		assert.Equal([]int{0, 0}, diffs[0].Path)
+		assert.Equal("~ 0.0: task 1.0→2.5", diffs[0].String())
+		assert.Equal([]interface{}{"task", "1.0→2.5"}, diffs[0].Row(false).FmtArgs())
+
+		assert.Equal(DiffAdded, diffs[1].Kind)
+		assert.Nil(diffs[1].Old)
+		assert.Equal("+ 1.1: review 0.5", diffs[1].String())
+
+		assert.Equal(DiffRemoved, diffs[2].Kind)
+		assert.Same(old.nodes[2], diffs[2].Old)
+		assert.Equal("- 2: carol 3.0", diffs[2].String())
+	}
+}
@@ -0,0 +1,53 @@
+package pprint
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Returns a tree of errs for error reports: each error is a row of its type and message, with the
+// errors it wraps as children. Both Unwrap() error and Unwrap() []error are followed. Messages are
+// trimmed of the ": cause" suffix when the cause is printed below, so that each row shows its own
+// part of the chain. Errors of several causes, e.g. of errors.Join(), are summarized as "2 errors"
+// instead, and the lines of other messages are joined by "; ", so that each error is a single line.
+// nil errors are skipped.
+func NewNodeFromErrors(errs ...error) *Node {
+	root := NewNode(WithColumns(NewColumn(WithLeftAlignment()), NewColumn(WithLeftAlignment())))
+	for _, err := range errs {
+		pushError(root, err)
+	}
+	return root
+}
+
+func pushError(n *Node, err error) {
+	if err == nil {
+		return
+	}
+
+	var causes []error
+	switch u := err.(type) {
+	case interface{ Unwrap() []error }:
+		causes = u.Unwrap()
+	default:
+		if c := errors.Unwrap(err); c != nil {
+			causes = []error{c}
+		}
+	}
+
+	var msg string
+	switch {
+	case len(causes) > 1:
+		msg = fmt.Sprintf("%d errors", len(causes))
+	case len(causes) == 1 && causes[0] != nil:
+		msg = strings.TrimSuffix(err.Error(), ": "+causes[0].Error())
+	default:
+		msg = err.Error()
+	}
+	msg = strings.ReplaceAll(msg, "\n", "; ")
+
+	c, _ := n.Push(fmt.Sprintf("%T", err), msg)
+	for _, cause := range causes {
+		pushError(c, cause)
+	}
+}
@@ -0,0 +1,41 @@
+package pprint
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewNodeFromErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	var (
+		base    = errors.New("disk full")
+		wrapped = fmt.Errorf("save: %w", &os.PathError{Op: "write", Path: "/tmp/x", Err: base})
+		multi   = errors.Join(errors.New("a\nretried"), fmt.Errorf("b: %w", base))
+	)
+
+	n := NewNodeFromErrors(wrapped, nil, multi)
+	assert.Equal(
+		"*fmt.wrapError      save        \n"+
+			"*fs.PathError       write /tmp/x\n"+
+			"*errors.errorString disk full   \n"+
+			"*errors.joinError   2 errors    \n"+
+			"*errors.errorString a; retried  \n"+
+			"*fmt.wrapError      b           \n"+
+			"*errors.errorString disk full   \n",
+		n.String(),
+	)
+	assert.Equal(2, n.NodesCount())
+	assert.Equal([]interface{}{"*fmt.wrapError", "save"}, n.nodes[0].Row().FmtArgs())
+	assert.Equal([]interface{}{"*fs.PathError", "write /tmp/x"}, n.nodes[0].nodes[0].Row().FmtArgs())
+	assert.Equal([]interface{}{"*errors.errorString", "disk full"}, n.nodes[0].nodes[0].nodes[0].Row().FmtArgs())
+	assert.Equal([]interface{}{"*errors.joinError", "2 errors"}, n.nodes[1].Row().FmtArgs(), "the causes are the children")
+	assert.Equal(2, n.nodes[1].NodesCount())
+	assert.Equal([]interface{}{"*fmt.wrapError", "b"}, n.nodes[1].nodes[1].Row().FmtArgs())
+
+	assert.Zero(NewNodeFromErrors().NodesCount())
+}
@@ -0,0 +1,83 @@
+package pprint
+
+import "reflect"
+
+// Counts pushes to order nodes by age.
+var pushes uint64
+
+// Picks the child to evict when a node has more children than WithMaxChildren() allows.
+// Returns an index of children.
+type EvictPolicy func(children []*Node) int
+
+// To cap the children of the node at n, e.g. for a live table of recent events kept by a
+// long-running daemon. When a push exceeds the cap, children picked by policy are removed and
+// detached. The pushed node itself could be evicted. nil policy means EvictOldest.
+// n <= 0 means no cap.
+func WithMaxChildren(n int, policy EvictPolicy) NodeOpt {
+	return func(nd *Node) {
+		if policy == nil {
+			policy = EvictOldest
+		}
+		nd.maxChildren, nd.evict = n, policy
+	}
+}
+
+// Evicts the child pushed first, regardless of sorting.
+func EvictOldest(children []*Node) int {
+	k := 0
+	for i, c := range children {
+		if c.seq < children[k].seq {
+			k = i
+		}
+	}
+	return k
+}
+
+// Returns a policy evicting the child with the lowest raw value in column col, compared by
+// MatchCmp(), e.g. to keep top talkers. The oldest goes first among equals. Children without the
+// column or with nil values are the lowest, values MatchCmp() can't compare fall back to EvictOldest.
+func EvictLowest(col int) EvictPolicy {
+	return func(children []*Node) int {
+		value := func(c *Node) interface{} {
+			if r := c.Row(); r != nil && col < len(r.fields) && col >= 0 {
+				return r.fields[col]
+			}
+			return nil
+		}
+
+		k := 0
+		for i, c := range children {
+			var (
+				a, b          = value(c), value(children[k])
+				less, greater bool
+			)
+			switch {
+			case a == nil && b == nil:
+			case a == nil:
+				less = true
+			case b == nil:
+				greater = true
+			default:
+				cmp := MatchCmp(a)
+				if cmp == nil || reflect.TypeOf(a) != reflect.TypeOf(b) {
+					return EvictOldest(children)
+				}
+				less, greater = cmp(a, b), cmp(b, a)
+			}
+			if less || (!greater && c.seq < children[k].seq) {
+				k = i
+			}
+		}
+		return k
+	}
+}
+
+// Evicts children while there are more than the cap.
+func (n *Node) enforceMaxChildren() {
+	for n.maxChildren > 0 && len(n.nodes) > n.maxChildren {
+		i := n.evict(n.nodes)
+		c := n.nodes[i]
+		n.nodes = append(n.nodes[:i], n.nodes[i+1:]...)
+		c.parent = nil
+	}
+}
@@ -0,0 +1,54 @@
+package pprint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeWithMaxChildren(t *testing.T) {
+	assert := assert.New(t)
+
+	{
+		n := NewNode(WithMaxChildren(2, nil))
+		a, _ := n.Push("a", 3)
+		n.Push("b", 1)
+		n.Sort(1)
+		n.Push("c", 2)
+		assert.Equal("b 1\nc 2\n", n.String(), "the oldest regardless of sorting")
+		assert.Nil(a.Parent())
+	}
+	{
+		n := NewNode(WithMaxChildren(2, EvictLowest(1)))
+		n.Push("a", 3)
+		n.Push("b", 1)
+		n.Push("c", 2)
+		assert.Equal("a 3\nc 2\n", n.String(), "top talkers")
+
+		d, _ := n.Push("d", 0)
+		assert.Nil(d.Parent(), "the pushed node could be evicted")
+		assert.Equal(2, n.NodesCount())
+
+		n.Push("e", 2)
+		assert.Equal("a 3\ne 2\n", n.String(), "the oldest among equals")
+	}
+	{
+		n := NewNode(WithMaxChildren(1, EvictLowest(0)))
+		n.Push(nil)
+		n.Push("x")
+		assert.Equal("x\n", n.String(), "nil values are the lowest")
+	}
+	{
+		n := NewNode(WithMaxChildren(1, EvictLowest(0)))
+		n.Push(1.5i)
+		n.Push(0.5i)
+		assert.Equal("(0+0.5i)\n", n.String(), "incomparable values fall back to the oldest")
+	}
+	{
+		n := NewNode(WithMaxChildren(0, nil))
+		for i := 0; i < 3; i++ {
+			n.Push(i)
+		}
+		assert.Equal(3, n.NodesCount(), "no cap")
+	}
+}
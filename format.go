@@ -0,0 +1,203 @@
+package pprint
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Render time.Time fields of the column with layout, e.g. time.RFC3339 or "Jan _2 15:04", instead
+// of the verbose default of time.Time. Times are converted to loc first unless it's nil. Other
+// fields are converted by MustToString(). Like WithFormatter(), it replaces other formatters.
+//
+// To format times of all columns, register a conversion with RegisterStringer().
+func WithTimeFormat(layout string, loc *time.Location) ColumnOpt {
+	return WithFormatter(func(v interface{}) string {
+		t, ok := v.(time.Time)
+		if !ok {
+			return MustToString(v)
+		}
+		if loc != nil {
+			t = t.In(loc)
+		}
+		return t.Format(layout)
+	})
+}
+
+// Render float fields of the column with n decimal places, e.g. 3.14159 as "3.14" with n = 2, so that
+// the decimal points of a right-aligned column line up. Other fields are converted by MustToString().
+// Like WithFormatter(), it replaces other formatters.
+func WithPrecision(n int) ColumnOpt {
+	if n < 0 {
+		n = 0
+	}
+	return WithFormatter(func(v interface{}) string {
+		switch f := v.(type) {
+		case float64:
+			return strconv.FormatFloat(f, 'f', n, 64)
+		case float32:
+			return strconv.FormatFloat(float64(f), 'f', n, 32)
+		}
+		return MustToString(v)
+	})
+}
+
+// Render integer and float fields of the column as byte sizes in binary units, e.g. 1048576 as
+// "1.0 MiB" and 512 as "512 B". Other fields are converted by MustToString(). Like WithFormatter(),
+// it replaces other formatters.
+func WithHumanBytes() ColumnOpt {
+	return WithFormatter(func(v interface{}) string {
+		f, ok := numeric(v)
+		if !ok {
+			return MustToString(v)
+		}
+		return humanBytes(f)
+	})
+}
+
+func humanBytes(f float64) string {
+	const units = "KMGTPE"
+
+	abs := math.Abs(f)
+	if abs < 1024 {
+		return strconv.FormatFloat(f, 'f', -1, 64) + " B"
+	}
+	i := 0
+	// Moves on at 1023.95, which would round to "1024.0".
+	for abs /= 1024; abs >= 1023.95 && i < len(units)-1; abs /= 1024 {
+		i++
+	}
+	if f < 0 {
+		abs = -abs
+	}
+	return fmt.Sprintf("%.1f %ciB", abs, units[i])
+}
+
+// Returns v as a float64 if it's an integer or a float, of any size.
+func numeric(v interface{}) (float64, bool) {
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// Render integer and float fields of the column as percentages with the given decimal places,
+// e.g. 0.256 as "25.6%" with 1 decimal, and align the column to the right. Other fields are converted
+// by MustToString(). Like WithFormatter(), it replaces other formatters.
+func WithPercent(decimals int) ColumnOpt {
+	if decimals < 0 {
+		decimals = 0
+	}
+	format := WithFormatter(func(v interface{}) string {
+		f, ok := numeric(v)
+		if !ok {
+			return MustToString(v)
+		}
+		return strconv.FormatFloat(f*100, 'f', decimals, 64) + "%"
+	})
+	return func(c *Column) {
+		format(c)
+		c.pad.right, c.pad.center = false, false
+	}
+}
+
+// How WithCurrency() renders money values.
+type CurrencyFormat struct {
+	// Prefix of the amounts, e.g. "$" or "€".
+	Symbol string
+
+	// Decimal places, amounts are rounded to them.
+	Decimals int
+
+	// Groups the digits of the integer part by three, e.g. ",". Empty means no grouping.
+	Thousands string
+
+	// Separates the decimals, e.g. "," for "1.234,50". Empty means ".".
+	Point string
+
+	// Renders negative amounts in parentheses, e.g. "($1,234.50)", instead of with a minus sign.
+	Parens bool
+}
+
+// Render integer and float fields of the column as money values, e.g. 1234.5 as "$1,234.50" with
+// CurrencyFormat{Symbol: "$", Decimals: 2, Thousands: ","}. Other fields are converted by
+// MustToString(). Like WithFormatter(), it replaces other formatters.
+func WithCurrency(cf CurrencyFormat) ColumnOpt {
+	if cf.Decimals < 0 {
+		cf.Decimals = 0
+	}
+	return WithFormatter(func(v interface{}) string {
+		f, ok := numeric(v)
+		if !ok {
+			return MustToString(v)
+		}
+		return cf.format(f)
+	})
+}
+
+func (cf CurrencyFormat) format(f float64) string {
+	s := strconv.FormatFloat(math.Abs(f), 'f', cf.Decimals, 64)
+	integer, frac, _ := strings.Cut(s, ".")
+	if cf.Thousands != "" {
+		var b strings.Builder
+		for i, d := range integer {
+			if i > 0 && (len(integer)-i)%3 == 0 {
+				b.WriteString(cf.Thousands)
+			}
+			b.WriteRune(d)
+		}
+		integer = b.String()
+	}
+	if s = cf.Symbol + integer; frac != "" {
+		point := cf.Point
+		if point == "" {
+			point = "."
+		}
+		s += point + frac
+	}
+
+	// Rounded to zero isn't negative.
+	if f >= 0 || strings.Trim(integer+frac, "0"+cf.Thousands) == "" {
+		return s
+	}
+	if cf.Parens {
+		return "(" + s + ")"
+	}
+	return "-" + s
+}
+
+// Returns a conversion of bool values into t or f, e.g. "✓" and "✗" or "yes" and "no", to render
+// booleans of all columns with RegisterStringer(BoolText("yes", "no")). See WithBoolText() for a
+// single column.
+func BoolText(t, f string) func(interface{}) (string, bool) {
+	return func(v interface{}) (string, bool) {
+		b, ok := v.(bool)
+		switch {
+		case !ok:
+			return "", false
+		case b:
+			return t, true
+		}
+		return f, true
+	}
+}
+
+// Render bool fields of the column as t or f instead of "true" and "false". Other fields are converted
+// by MustToString(). Like WithFormatter(), it replaces other formatters.
+func WithBoolText(t, f string) ColumnOpt {
+	text := BoolText(t, f)
+	return WithFormatter(func(v interface{}) string {
+		if s, ok := text(v); ok {
+			return s
+		}
+		return MustToString(v)
+	})
+}
@@ -0,0 +1,128 @@
+package pprint
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTimeFormat(t *testing.T) {
+	assert := assert.New(t)
+
+	var (
+		tm    = time.Date(2021, 3, 4, 22, 30, 0, 0, time.UTC)
+		tokyo = time.FixedZone("JST", 9*3600)
+	)
+	tests := map[string]struct {
+		col  Column
+		in   interface{}
+		want string
+	}{
+		"layout":   {NewColumn(WithTimeFormat("2006-01-02 15:04", nil)), tm, "2021-03-04 22:30"},
+		"location": {NewColumn(WithTimeFormat(time.RFC3339, tokyo)), tm, "2021-03-05T07:30:00+09:00"},
+		"others":   {NewColumn(WithTimeFormat(time.RFC3339, nil)), 42, "42"},
+		"nil":      {NewColumn(WithTimeFormat(time.RFC3339, nil)), nil, ""},
+	}
+	for name, tc := range tests {
+		r := NewRow(WithRowColumns(tc.col), WithRowData(tc.in))
+		assert.Equal([]interface{}{tc.want}, r.FmtArgs(), name)
+		assert.Equal(tc.in, r.fields[0], name)
+	}
+}
+
+func TestWithPrecision(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode(WithColumns(NewColumn(WithPrecision(2))))
+	for _, v := range []interface{}{3.14159, float32(10.5), -0.001, 7, "n/a"} {
+		n.Push(v)
+	}
+	assert.Equal(" 3.14\n10.50\n-0.00\n    7\n  n/a\n", n.String())
+
+	r := NewRow(WithRowColumns(NewColumn(WithPrecision(-1))), WithRowData(2.5))
+	assert.Equal([]interface{}{"2"}, r.FmtArgs(), "negative precision as 0")
+}
+
+func TestWithHumanBytes(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		in   interface{}
+		want string
+	}{
+		"bytes":     {512, "512 B"},
+		"zero":      {uint64(0), "0 B"},
+		"kibibytes": {int64(1536), "1.5 KiB"},
+		"mebibytes": {1048576, "1.0 MiB"},
+		"gibibytes": {float64(3 << 30), "3.0 GiB"},
+		"exbibytes": {uint64(1) << 63, "8.0 EiB"},
+		"negative":  {-2048, "-2.0 KiB"},
+		"rounding":  {1048575, "1.0 MiB"},
+		"others":    {"n/a", "n/a"},
+		"nil":       {nil, ""},
+	}
+	for name, tc := range tests {
+		r := NewRow(WithRowColumns(NewColumn(WithHumanBytes())), WithRowData(tc.in))
+		assert.Equal([]interface{}{tc.want}, r.FmtArgs(), name)
+	}
+}
+
+func TestWithPercent(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode(WithColumns(NewColumn(WithLeftAlignment(), WithPercent(1))))
+	for _, v := range []interface{}{0.256, 1, float32(0.5), -0.0125, "n/a"} {
+		n.Push(v)
+	}
+	assert.Equal(" 25.6%\n100.0%\n 50.0%\n -1.2%\n   n/a\n", n.String(), "right-aligned")
+
+	r := NewRow(WithRowColumns(NewColumn(WithPercent(-1))), WithRowData(0.5))
+	assert.Equal([]interface{}{"50%"}, r.FmtArgs())
+}
+
+func TestWithCurrency(t *testing.T) {
+	assert := assert.New(t)
+
+	var (
+		usd = CurrencyFormat{Symbol: "$", Decimals: 2, Thousands: ","}
+		acc = CurrencyFormat{Symbol: "€", Decimals: 2, Thousands: ".", Point: ",", Parens: true}
+		yen = CurrencyFormat{Symbol: "¥"}
+	)
+	tests := map[string]struct {
+		cf   CurrencyFormat
+		in   interface{}
+		want string
+	}{
+		"grouped":          {usd, 1234.5, "$1,234.50"},
+		"small":            {usd, 12, "$12.00"},
+		"millions":         {usd, int64(-1234567), "-$1,234,567.00"},
+		"parens":           {acc, -1234.5, "(€1.234,50)"},
+		"no decimals":      {yen, 1234.6, "¥1235"},
+		"rounded to zero":  {usd, -0.001, "$0.00"},
+		"others":           {usd, "n/a", "n/a"},
+		"negative decimal": {CurrencyFormat{Decimals: -1}, 2.4, "2"},
+	}
+	for name, tc := range tests {
+		r := NewRow(WithRowColumns(NewColumn(WithCurrency(tc.cf))), WithRowData(tc.in))
+		assert.Equal([]interface{}{tc.want}, r.FmtArgs(), name)
+	}
+}
+
+func TestWithBoolText(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode(WithColumns(NewColumn(WithBoolText("✓", "✗")), NewColumn()))
+	n.Push(true, true)
+	n.Push(false, false)
+	n.Push(nil, "x")
+	assert.Equal("✓  true\n✗ false\n      x\n", n.String())
+
+	saved, _ := stringers.Load().([]func(interface{}) (string, bool))
+	defer stringers.Store(saved)
+	RegisterStringer(BoolText("yes", "no"))
+
+	m := NewNode()
+	m.Push(true, false, 1)
+	assert.Equal("yes no 1\n", m.String(), "globally")
+}
@@ -0,0 +1,265 @@
+package pprint
+
+import (
+	"encoding/json"
+	"html"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Content types served by Handler().
+const (
+	contentText = "text/plain; charset=utf-8"
+	contentHTML = "text/html; charset=utf-8"
+	contentJSON = "application/json"
+)
+
+// Returns a handler serving the tree returned by fn, so that small admin endpoints can reuse
+// trees directly. The content type is negotiated by the Accept header of the request:
+//
+// text/plain: aligned text printed with opts, the default. It's plain, see WithPlain(), as clients
+// aren't terminals.
+//
+// text/html: a table, columns having WithWeight() get their shares as widths and cells of columns
+// having WithLinkTemplate() are links.
+//
+// application/json: an array of the rows the text would have, each an array of the values of the
+// printed columns the same as SinkNDJSON writes.
+//
+// All of them print the rows and columns opts select, e.g. by WithMaxDepth() or
+// WithVisibleColumns().
+//
+// Titles set by WithHeader() in opts head the HTML table as well, cells set by WithFooter() foot its
+// last table, fitted to the columns of that table, and the title set by WithTitle() becomes its
+// caption below it. Responds 404 if fn returns nil.
+func Handler(fn func(r *http.Request) *Node, opts ...PrintingOpt) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := fn(r)
+		if n == nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		ctype := negotiate(r.Header.Get("Accept"))
+		w.Header().Set("Content-Type", ctype)
+		switch ctype {
+		case contentHTML:
+			writeHTML(w, n, NewPrinting(opts...))
+		case contentJSON:
+			writeJSON(w, n, NewPrinting(opts...))
+		default:
+			Print(n, append(append([]PrintingOpt(nil), opts...), WithWriter(w), WithPlain())...)
+		}
+	})
+}
+
+// Returns the content type of the highest quality in accept, text/plain if none is supported.
+// Wildcards match text/plain.
+func negotiate(accept string) string {
+	var (
+		best = contentText
+		q    = -1.0
+	)
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+
+		var ctype string
+		switch strings.ToLower(strings.TrimSpace(params[0])) {
+		case "text/html":
+			ctype = contentHTML
+		case "application/json":
+			ctype = contentJSON
+		case "text/plain", "text/*", "*/*":
+			ctype = contentText
+		default:
+			continue
+		}
+
+		pq := 1.0
+		for _, p := range params[1:] {
+			if kv := strings.SplitN(strings.TrimSpace(p), "=", 2); len(kv) == 2 && kv[0] == "q" {
+				if f, err := strconv.ParseFloat(kv[1], 64); err == nil {
+					pq = f
+				}
+			}
+		}
+		if pq > q && pq > 0 {
+			best, q = ctype, pq
+		}
+	}
+	return best
+}
+
+// Writes the rows of n RunNode() would print as an HTML table, markers of hidden descendants span
+// the table. A new table starts when the schema changes.
+func writeHTML(w io.Writer, n *Node, p *Printing) {
+	var (
+		b    strings.Builder
+		last *ColumnSchema
+	)
+	flush := func() {
+		io.WriteString(w, b.String())
+		b.Reset()
+	}
+
+	for _, st := range p.steps(n) {
+		if st.hidden > 0 {
+			if last != nil {
+				span := 0
+				for i := range last.cols {
+					if p.shows(last, i) {
+						span++
+					}
+				}
+				b.WriteString(`<tr><td colspan="` + strconv.Itoa(span) + `">` + html.EscapeString(p.markerText(st.hidden)) + "</td></tr>\n")
+				flush()
+			}
+			continue
+		}
+		r := st.node.Row()
+		if r == nil || r.schema.count == 0 {
+			continue
+		}
+
+		if r.schema != last {
+			if last != nil {
+				writeHTMLTail(&b, last, p, false)
+			}
+			writeHTMLHead(&b, r.schema, p, last == nil)
+			last = r.schema
+		}
+
+		b.WriteString("<tr>")
+		for i, a := range r.fmtArgs {
+			c := r.schema.cols[i]
+			if !p.shows(r.schema, i) {
+				continue
+			}
+			b.WriteString("<td" + htmlAlign(c) + ">")
+			cell := html.EscapeString(stripANSI(a.(string)))
+			if u := c.href(a.(string)); u != "" {
+				cell = `<a href="` + html.EscapeString(u) + `">` + cell + "</a>"
+			}
+			b.WriteString(cell)
+			b.WriteString("</td>")
+		}
+		b.WriteString("</tr>\n")
+		flush()
+	}
+	if last != nil {
+		writeHTMLTail(&b, last, p, true)
+	}
+	flush()
+}
+
+// Writes the opening of a table of s, with the title and the header of p if first.
+func writeHTMLHead(b *strings.Builder, s *ColumnSchema, p *Printing, first bool) {
+	b.WriteString("<table>\n")
+	if first && p.title != "" {
+		b.WriteString(`<caption style="caption-side: bottom">` + html.EscapeString(stripANSI(p.title)) + "</caption>\n")
+	}
+
+	sum := 0
+	for i, c := range s.cols {
+		if p.shows(s, i) {
+			sum += c.weight
+		}
+	}
+	if sum > 0 {
+		b.WriteString("<colgroup>")
+		for i, c := range s.cols {
+			if !p.shows(s, i) {
+				continue
+			}
+			if c.weight > 0 {
+				b.WriteString(`<col style="width: ` + strconv.Itoa(c.weight*100/sum) + `%">`)
+			} else {
+				b.WriteString("<col>")
+			}
+		}
+		b.WriteString("</colgroup>\n")
+	}
+
+	if header := p.headerOf(s); first && header != nil {
+		b.WriteString("<thead><tr>")
+		writeHTMLTitles(b, s, p, "th", header)
+		b.WriteString("</tr></thead>\n")
+	}
+	b.WriteString("<tbody>\n")
+}
+
+// Writes the closing of a table of s, with the footer of p if last.
+func writeHTMLTail(b *strings.Builder, s *ColumnSchema, p *Printing, last bool) {
+	b.WriteString("</tbody>")
+	if last && p.footer != nil {
+		b.WriteString("\n<tfoot><tr>")
+		writeHTMLTitles(b, s, p, "td", p.footer)
+		b.WriteString("</tr></tfoot>\n")
+	}
+	b.WriteString("</table>\n")
+}
+
+// Writes the header titles or the footer cells as cells of tag, shrinked or enlarged to fit s.
+func writeHTMLTitles(b *strings.Builder, s *ColumnSchema, p *Printing, tag string, titles []interface{}) {
+	for i := 0; i < s.count; i++ {
+		var a interface{}
+		if i < len(titles) {
+			a = titles[i]
+		}
+		if !p.shows(s, i) {
+			continue
+		}
+		b.WriteString("<" + tag + htmlAlign(s.cols[i]) + ">")
+		b.WriteString(html.EscapeString(stripANSI(MustToString(a))))
+		b.WriteString("</" + tag + ">")
+	}
+}
+
+// Returns the style attribute aligning cells of c.
+func htmlAlign(c Column) string {
+	switch {
+	case c.pad.center:
+		return ` style="text-align: center"`
+	case c.pad.right:
+		return ""
+	default:
+		return ` style="text-align: right"`
+	}
+}
+
+// Writes the rows of n RunNode() would print as a JSON array of arrays of the printed columns.
+// Markers of hidden descendants are skipped.
+func writeJSON(w io.Writer, n *Node, p *Printing) {
+	io.WriteString(w, "[")
+	first := true
+	for _, st := range p.steps(n) {
+		r := st.node.Row()
+		if st.hidden > 0 || r == nil || r.schema.count == 0 {
+			continue
+		}
+
+		var values, strs []interface{}
+		for i, a := range r.fmtArgs {
+			if p.shows(r.schema, i) {
+				values = append(values, jsonValue(r.fields[i], stripANSI(a.(string))))
+				strs = append(strs, stripANSI(a.(string)))
+			}
+		}
+		if values == nil {
+			values = []interface{}{}
+		}
+		b, err := json.Marshal(values)
+		if err != nil {
+			// A failing json.Marshaler, falls back to the string representations.
+			b, _ = json.Marshal(strs)
+		}
+		if !first {
+			io.WriteString(w, ",")
+		}
+		w.Write(b)
+		first = false
+	}
+	io.WriteString(w, "]\n")
+}
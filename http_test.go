@@ -0,0 +1,165 @@
+package pprint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := map[string]struct {
+		accept   string
+		expected string
+	}{
+		"none":          {"", contentText},
+		"unsupported":   {"image/png", contentText},
+		"html":          {"text/html,application/xhtml+xml,*/*;q=0.8", contentHTML},
+		"json":          {"application/json", contentJSON},
+		"by quality":    {"text/html;q=0.5, application/json;q=0.9", contentJSON},
+		"wildcard":      {"*/*", contentText},
+		"refused":       {"text/html;q=0", contentText},
+		"first of ties": {"application/json, text/html", contentJSON},
+	}
+	for name, test := range tests {
+		assert.Equal(t, test.expected, negotiate(test.accept), name)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	newNode := func(r *http.Request) *Node {
+		if r.URL.Path != "/" {
+			return nil
+		}
+		n := NewNode(WithColumns(
+			NewColumn(WithLeftAlignment(), WithWeight(1), WithLinkTemplate("/users/{value}")),
+			NewColumn(),
+			NewColumn(WithCenterAlignment(), WithWeight(3)),
+		))
+		a, _ := n.Push("alice", 30, "<admin>")
+		a.Push("bob", 4, true)
+		return n
+	}
+	h := Handler(newNode, WithHeader("NAME", "AGE"), WithTitle("Users"))
+
+	tests := map[string]struct {
+		accept string
+		ctype  string
+		body   string
+	}{
+		"text": {
+			"text/plain",
+			contentText,
+			"Users\n" +
+				"NAME         AGE        \n" +
+				"/users/alice  30 <admin>\n" +
+				"/users/bob     4  true  \n",
+		},
+		"html": {
+			"text/html",
+			contentHTML,
+			"<table>\n" +
+				`<caption style="caption-side: bottom">Users</caption>` + "\n" +
+				`<colgroup><col style="width: 25%"><col><col style="width: 75%"></colgroup>` + "\n" +
+				`<thead><tr><th>NAME</th><th style="text-align: right">AGE</th><th style="text-align: center"></th></tr></thead>` + "\n" +
+				"<tbody>\n" +
+				`<tr><td><a href="/users/alice">alice</a></td><td style="text-align: right">30</td><td style="text-align: center">&lt;admin&gt;</td></tr>` + "\n" +
+				`<tr><td><a href="/users/bob">bob</a></td><td style="text-align: right">4</td><td style="text-align: center">true</td></tr>` + "\n" +
+				"</tbody></table>\n",
+		},
+		"json": {
+			"application/json",
+			contentJSON,
+			`[["alice",30,"\u003cadmin\u003e"],["bob",4,true]]` + "\n",
+		},
+	}
+	for name, test := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept", test.accept)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code, name)
+		assert.Equal(t, test.ctype, rec.Header().Get("Content-Type"), name)
+		assert.Equal(t, test.body, rec.Body.String(), name)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/missing", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	narrow := Handler(newNode, WithHeader("NAME", "AGE"), WithVisibleColumns(0, 2), WithFooter("2 users", 34, "<end>"))
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept", "text/html")
+	rec = httptest.NewRecorder()
+	narrow.ServeHTTP(rec, req)
+	assert.Equal(t,
+		"<table>\n"+
+			`<colgroup><col style="width: 25%"><col style="width: 75%"></colgroup>`+"\n"+
+			`<thead><tr><th>NAME</th><th style="text-align: center"></th></tr></thead>`+"\n"+
+			"<tbody>\n"+
+			`<tr><td><a href="/users/alice">alice</a></td><td style="text-align: center">&lt;admin&gt;</td></tr>`+"\n"+
+			`<tr><td><a href="/users/bob">bob</a></td><td style="text-align: center">true</td></tr>`+"\n"+
+			"</tbody>\n"+
+			`<tfoot><tr><td>2 users</td><td style="text-align: center">&lt;end&gt;</td></tr></tfoot>`+"\n"+
+			"</table>\n",
+		rec.Body.String(), "visible columns and footer")
+}
+
+func TestHandlerSteps(t *testing.T) {
+	n := NewNode()
+	a, _ := n.Push("usr", 10)
+	b, _ := a.Push("lib", 7)
+	b.Push("libc.so", 2)
+	n.Push("etc", 5)
+
+	tests := map[string]struct {
+		node *Node
+		opts []PrintingOpt
+		rows string
+		json string
+	}{
+		"max depth": {
+			n,
+			[]PrintingOpt{WithMaxDepth(1), WithDepthMarker()},
+			`<tr><td style="text-align: right">usr</td><td style="text-align: right">10</td></tr>` + "\n" +
+				`<tr><td colspan="2">… 2 hidden descendants</td></tr>` + "\n" +
+				`<tr><td style="text-align: right">etc</td><td style="text-align: right">5</td></tr>` + "\n",
+			`[["usr",10],["etc",5]]`,
+		},
+		"leaves only": {
+			n,
+			[]PrintingOpt{WithLeavesOnly()},
+			`<tr><td style="text-align: right">libc.so</td><td style="text-align: right">2</td></tr>` + "\n" +
+				`<tr><td style="text-align: right">etc</td><td style="text-align: right">5</td></tr>` + "\n",
+			`[["libc.so",2],["etc",5]]`,
+		},
+		"skip receiver row": {
+			b,
+			[]PrintingOpt{WithSkipReceiverRow()},
+			`<tr><td style="text-align: right">libc.so</td><td style="text-align: right">2</td></tr>` + "\n",
+			`[["libc.so",2]]`,
+		},
+		"visible columns": {
+			b,
+			[]PrintingOpt{WithVisibleColumns(1)},
+			`<tr><td style="text-align: right">7</td></tr>` + "\n" +
+				`<tr><td style="text-align: right">2</td></tr>` + "\n",
+			`[[7],[2]]`,
+		},
+	}
+	for name, test := range tests {
+		h := Handler(func(*http.Request) *Node { return test.node }, test.opts...)
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept", "text/html")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		assert.Equal(t, "<table>\n<tbody>\n"+test.rows+"</tbody></table>\n", rec.Body.String(), name)
+
+		req.Header.Set("Accept", "application/json")
+		rec = httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		assert.Equal(t, test.json+"\n", rec.Body.String(), name)
+	}
+}
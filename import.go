@@ -0,0 +1,369 @@
+package pprint
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Builds a node from string records, e.g. read by encoding/csv, each record becomes a child of the
+// returned node. The first record decides the column count. Returns any error encountered.
+//
+// Import options are:
+//
+// WithNumericDetection(): convert columns of numeric strings into int or float64.
+//
+// WithHeaderRow(): take the first record as the titles of the columns, see NewNodeFromCSV().
+func NewNodeFromRecords(records [][]string, opts ...ImportOpt) (*Node, error) {
+	im := &importing{}
+	for _, opt := range opts {
+		opt(im)
+	}
+
+	n := NewNode()
+	if im.header && len(records) > 0 {
+		cols := make([]Column, len(records[0]))
+		for i := range cols {
+			cols[i] = NewColumn(WithColumnName(records[0][i]))
+		}
+		n = NewNode(WithColumns(cols...))
+		records = records[1:]
+	}
+
+	rows := make([][]interface{}, len(records))
+	for i, rec := range records {
+		rows[i] = make([]interface{}, len(rec))
+		for j, s := range rec {
+			rows[i][j] = s
+		}
+	}
+	im.convert(rows)
+
+	for _, row := range rows {
+		if _, err := n.Push(row...); err != nil {
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+// Builds a node from CSV read from r, e.g. to format CSV into an aligned table. Records may have
+// different numbers of fields. Returns the titles of the columns if WithHeaderRow() is given, e.g.
+// for WithHeader(), and any error encountered. Accepts the options of NewNodeFromRecords().
+func NewNodeFromCSV(r io.Reader, opts ...ImportOpt) (*Node, []interface{}, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("NewNodeFromCSV: %v", err)
+	}
+
+	im := &importing{}
+	for _, opt := range opts {
+		opt(im)
+	}
+	var titles []interface{}
+	if im.header && len(records) > 0 {
+		for _, s := range records[0] {
+			titles = append(titles, s)
+		}
+	}
+
+	n, err := NewNodeFromRecords(records, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return n, titles, nil
+}
+
+// Builds a node from maps, each map becomes a child of the returned node and each key a column
+// named by it.
+// Returns the titles of the columns, e.g. for WithHeader().
+//
+// Columns are the given ones in order, or all the keys of the maps sorted if none is given, so that
+// the layout doesn't depend on the order of map iteration. Missing keys are nil fields.
+func NewNodeFromMaps(maps []map[string]interface{}, columns ...string) (*Node, []interface{}) {
+	if len(columns) == 0 {
+		seen := map[string]bool{}
+		for _, m := range maps {
+			for k := range m {
+				if !seen[k] {
+					seen[k] = true
+					columns = append(columns, k)
+				}
+			}
+		}
+		sort.Strings(columns)
+	}
+
+	var (
+		cols   = make([]Column, len(columns))
+		titles = make([]interface{}, len(columns))
+	)
+	for i, c := range columns {
+		cols[i], titles[i] = NewColumn(WithColumnName(c)), c
+	}
+	n := NewNode(WithColumns(cols...))
+	for _, m := range maps {
+		row := make([]interface{}, len(columns))
+		for i, c := range columns {
+			row[i] = m[c]
+		}
+		n.Push(row...)
+	}
+	return n, titles
+}
+
+// Builds a node from JSON read from r, an array of objects or a single object. Each object becomes a
+// child of the returned node and each key a column named by it, sorted as in NewNodeFromMaps(). Returns the
+// titles of the columns, e.g. for WithHeader(), and any error encountered.
+//
+// Values that are objects or arrays of objects aren't columns. Each becomes a child of the row of its
+// object, named by and holding the key, e.g. for Node.Child(), and the objects become its children
+// with columns of their own. Keys holding them in any object aren't columns either, their other
+// values but nulls become single rows under the key the same way. Integers are int, other numbers
+// float64.
+func NewNodeFromJSON(r io.Reader) (*Node, []interface{}, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, nil, fmt.Errorf("NewNodeFromJSON: %v", err)
+	}
+	objs, ok := jsonObjects(v)
+	if !ok {
+		return nil, nil, fmt.Errorf("NewNodeFromJSON: not an object or an array of objects")
+	}
+	n, titles, err := nodeFromObjects(objs, NewSchema(NewColumn(WithLeftAlignment())))
+	if err != nil {
+		return nil, nil, fmt.Errorf("NewNodeFromJSON: %v", err)
+	}
+	return n, titles, nil
+}
+
+// Returns v as objects if it's an object or an array of objects.
+func jsonObjects(v interface{}) ([]map[string]interface{}, bool) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return []map[string]interface{}{v}, true
+	case []interface{}:
+		objs := make([]map[string]interface{}, len(v))
+		for i, e := range v {
+			o, ok := e.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			objs[i] = o
+		}
+		return objs, len(v) > 0
+	}
+	return nil, false
+}
+
+// Builds a node from decoded JSON objects, nested objects become subtrees under rows of their keys
+// of schema keys.
+func nodeFromObjects(objs []map[string]interface{}, keys *ColumnSchema) (*Node, []interface{}, error) {
+	var (
+		columns []string
+		nested  = map[string]bool{}
+		seen    = map[string]bool{}
+	)
+	for _, o := range objs {
+		for k, v := range o {
+			if _, ok := jsonObjects(v); ok {
+				nested[k] = true
+			}
+		}
+	}
+	for _, o := range objs {
+		for k := range o {
+			if !nested[k] && !seen[k] {
+				seen[k] = true
+				columns = append(columns, k)
+			}
+		}
+	}
+	sort.Strings(columns)
+
+	var (
+		cols   = make([]Column, len(columns))
+		titles = make([]interface{}, len(columns))
+	)
+	for i, c := range columns {
+		cols[i], titles[i] = NewColumn(WithColumnName(c)), c
+	}
+	n := NewNode(WithColumns(cols...))
+	for _, o := range objs {
+		row := make([]interface{}, len(columns))
+		for i, c := range columns {
+			row[i] = jsonNumber(o[c])
+		}
+		c, err := n.Push(row...)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// Subtrees in the order of their keys, each under its own node to keep its own columns.
+		names := make([]string, 0, len(o))
+		for k := range o {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		for _, k := range names {
+			if !nested[k] || o[k] == nil {
+				continue
+			}
+			g, err := c.PushNode(NewNode(WithName(k), WithRow(NewRow(WithRowSchema(keys), WithRowData(k)))))
+			if err != nil {
+				return nil, nil, err
+			}
+			inner, ok := jsonObjects(o[k])
+			if !ok {
+				// A value of a key holding objects in other records, with a column of its own
+				if _, err := g.PushRow(NewRow(WithRowData(jsonNumber(o[k])))); err != nil {
+					return nil, nil, err
+				}
+				continue
+			}
+			sub, _, err := nodeFromObjects(inner, keys)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, m := range sub.Children() {
+				sub.Remove(m)
+				if _, err := g.PushNode(m); err != nil {
+					return nil, nil, err
+				}
+			}
+		}
+	}
+	return n, titles, nil
+}
+
+// Converts a json.Number into int, or float64 if it isn't an integer.
+func jsonNumber(v interface{}) interface{} {
+	num, ok := v.(json.Number)
+	if !ok {
+		return v
+	}
+	if i, err := strconv.Atoi(string(num)); err == nil {
+		return i
+	}
+	f, _ := num.Float64()
+	return f
+}
+
+type ImportOpt func(*importing)
+
+type importing struct {
+	numeric bool
+
+	// The first record holds the titles.
+	header bool
+}
+
+// Applies the conversions asked by the options to the string values of rows in place.
+func (im *importing) convert(rows [][]interface{}) {
+	if im.numeric {
+		detectNumbers(rows)
+	}
+}
+
+// Convert the values of a column into int, or float64, when every non-empty string value of that
+// column parses, e.g. "42", "-3.14", "1,234". Empty strings of such columns become nil.
+// It enables numeric sorting and aggregation on imported text data.
+func WithNumericDetection() ImportOpt {
+	return func(im *importing) {
+		im.numeric = true
+	}
+}
+
+// Take the first record as the titles of the columns instead of a row, it decides the column count
+// and names the columns then. Only NewNodeFromCSV() returns the titles, NewNodeFromRecords() drops the record.
+func WithHeaderRow() ImportOpt {
+	return func(im *importing) {
+		im.header = true
+	}
+}
+
+var (
+	// Matches decimal numbers, e.g. "42", "-3.14", "1e3". Words like "NaN" and "Inf" aren't numbers.
+	numberRe = regexp.MustCompile(`^[-+]?(\d+\.?\d*|\.\d+)([eE][-+]?\d+)?$`)
+
+	// Matches numbers with comma thousands separators, e.g. "1,234", "-1,234,567.5".
+	thousandsRe = regexp.MustCompile(`^[-+]?\d{1,3}(,\d{3})+(\.\d+)?$`)
+)
+
+// Converts columns of rows holding numeric strings only into int or float64.
+func detectNumbers(rows [][]interface{}) {
+	cols := 0
+	for _, row := range rows {
+		if len(row) > cols {
+			cols = len(row)
+		}
+	}
+
+	for col := 0; col < cols; col++ {
+		var (
+			ints, floats = true, true
+			seen         = false
+		)
+		for _, row := range rows {
+			if col >= len(row) {
+				continue
+			}
+			s, ok := row[col].(string)
+			if !ok {
+				ints, floats = false, false
+				break
+			}
+			if s = normalizeNumber(s); s == "" {
+				continue
+			}
+			seen = true
+			if !numberRe.MatchString(s) {
+				floats = false
+				break
+			}
+			if _, err := strconv.Atoi(s); err != nil {
+				ints = false
+			}
+			if _, err := strconv.ParseFloat(s, 64); err != nil {
+				floats = false
+				break
+			}
+		}
+		if !seen || !floats {
+			continue
+		}
+
+		for _, row := range rows {
+			if col >= len(row) {
+				continue
+			}
+			switch s := normalizeNumber(row[col].(string)); {
+			case s == "":
+				row[col] = nil
+			case ints:
+				row[col], _ = strconv.Atoi(s)
+			default:
+				row[col], _ = strconv.ParseFloat(s, 64)
+			}
+		}
+	}
+}
+
+// Trims s and removes thousands separators.
+func normalizeNumber(s string) string {
+	s = strings.TrimSpace(s)
+	if thousandsRe.MatchString(s) {
+		s = strings.ReplaceAll(s, ",", "")
+	}
+	return s
+}
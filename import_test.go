@@ -0,0 +1,133 @@
+package pprint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewNodeFromRecords(t *testing.T) {
+	assert := assert.New(t)
+
+	records := [][]string{
+		{"a", "42", "3.14", "1,234", "1,2", "1e3"},
+		{"b", "-7", "2", "", "3", "NaN"},
+		{"c", " 0 ", "-0.5", "12,345,678.5", "4", "inf"},
+	}
+
+	{
+		n, err := NewNodeFromRecords(records)
+		assert.NoError(err)
+		assert.Equal(3, n.NodesCount())
+		assert.Equal([]interface{}{"a", "42", "3.14", "1,234", "1,2", "1e3"}, n.nodes[0].Row().fields)
+	}
+	{
+		n, err := NewNodeFromRecords(records, WithNumericDetection())
+		assert.NoError(err)
+		assert.Equal([]interface{}{"a", 42, 3.14, 1234.0, "1,2", "1e3"}, n.nodes[0].Row().fields)
+		assert.Equal([]interface{}{"b", -7, 2.0, nil, "3", "NaN"}, n.nodes[1].Row().fields)
+		assert.Equal([]interface{}{"c", 0, -0.5, 12345678.5, "4", "inf"}, n.nodes[2].Row().fields)
+
+		assert.NoError(n.Sort(1))
+		assert.Equal("b", n.nodes[0].Row().fields[0], "sorted as numbers")
+	}
+	{
+		n, err := NewNodeFromRecords(nil, WithNumericDetection())
+		assert.NoError(err)
+		assert.Equal(0, n.NodesCount())
+	}
+}
+
+func TestNewNodeFromMaps(t *testing.T) {
+	assert := assert.New(t)
+
+	maps := []map[string]interface{}{
+		{"name": "alice", "age": 30},
+		{"name": "bob", "role": "admin"},
+	}
+
+	n, header := NewNodeFromMaps(maps)
+	assert.Equal([]interface{}{"age", "name", "role"}, header, "sorted keys")
+	assert.Equal("30 alice      \n     bob admin\n", n.String())
+
+	n, header = NewNodeFromMaps(maps, "role", "name")
+	assert.Equal([]interface{}{"role", "name"}, header)
+	assert.Equal("      alice\nadmin   bob\n", n.String())
+
+	n, header = NewNodeFromMaps(nil)
+	assert.Empty(header)
+	assert.Equal(0, n.NodesCount())
+}
+
+func TestNewNodeFromCSV(t *testing.T) {
+	assert := assert.New(t)
+
+	in := "name,qty,note\n" +
+		"apple,3\n" +
+		"\"kiwi, gold\",12,ripe\n"
+
+	n, header, err := NewNodeFromCSV(strings.NewReader(in), WithHeaderRow(), WithNumericDetection())
+	assert.NoError(err)
+	assert.Equal([]interface{}{"name", "qty", "note"}, header)
+	assert.Equal("     apple  3     \nkiwi, gold 12 ripe\n", n.String())
+	assert.Equal(12, n.ChildAt(1).Row().fields[1])
+
+	n, header, err = NewNodeFromCSV(strings.NewReader(in))
+	assert.NoError(err)
+	assert.Nil(header)
+	assert.Equal(3, n.NodesCount())
+
+	_, _, err = NewNodeFromCSV(strings.NewReader("a,\"b\n"))
+	assert.Error(err)
+
+	n, err = NewNodeFromRecords([][]string{{"a", "b", "c"}, {"1"}}, WithHeaderRow())
+	assert.NoError(err)
+	assert.Equal("1  \n", n.String(), "the header decides the column count")
+}
+
+func TestNewNodeFromJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	in := `[
+		{"name": "alice", "age": 30, "score": 9.5, "tags": ["a", "b"],
+		 "pets": [{"kind": "cat"}, {"kind": "dog"}]},
+		{"name": "bob", "age": 4, "admin": true, "home": {"city": "Oslo"}}
+	]`
+	n, header, err := NewNodeFromJSON(strings.NewReader(in))
+	assert.NoError(err)
+	assert.Equal([]interface{}{"admin", "age", "name", "score", "tags"}, header)
+	assert.Equal(
+		"     30 alice 9.5 [a b]\n"+
+			"pets\n"+
+			"cat\n"+
+			"dog\n"+
+			"true  4   bob          \n"+
+			"home\n"+
+			"Oslo\n",
+		n.String(),
+	)
+	assert.Equal("dog", n.ChildAt(0).Child("pets").ChildAt(1).Row().fields[0])
+	assert.Equal(30, n.ChildAt(0).Row().fields[1], "integers")
+	assert.Equal(9.5, n.ChildAt(0).Row().fields[3])
+
+	n, _, err = NewNodeFromJSON(strings.NewReader(`[{"a": 1, "x": [{"p": 1}], "y": [{"q": 2, "r": 3}]}]`))
+	assert.NoError(err)
+	assert.Equal("1\nx\n1\ny\n2 3\n", n.String(), "nested keys of different columns")
+	assert.NoError(n.Validate())
+
+	n, header, err = NewNodeFromJSON(strings.NewReader(`[{"a": 1, "home": {"city": "Oslo"}}, {"a": 2, "home": "n/a"}, {"a": 3, "home": null}]`))
+	assert.NoError(err)
+	assert.Equal([]interface{}{"a"}, header, "never a column")
+	assert.Equal("1\nhome\nOslo\n2\nhome\nn/a\n3\n", n.String(), "mixed records")
+	assert.NoError(n.Validate())
+
+	n, _, err = NewNodeFromJSON(strings.NewReader(`{"a": 1}`))
+	assert.NoError(err)
+	assert.Equal("1\n", n.String(), "single object")
+
+	_, _, err = NewNodeFromJSON(strings.NewReader(`[1, 2]`))
+	assert.EqualError(err, "NewNodeFromJSON: not an object or an array of objects")
+	_, _, err = NewNodeFromJSON(strings.NewReader(`[`))
+	assert.Error(err)
+}
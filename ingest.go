@@ -0,0 +1,341 @@
+package pprint
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Settings of turning fields into strings, shared by a node, its descendants and their rows.
+type ingestion struct {
+	// Memoizes the string representations of Stringers, could be nil.
+	cache stringCache
+
+	// Cleans up the string representations, could be nil.
+	sanitize *sanitizer
+
+	// Levels of slices and structs expanded into columns, 0 means no expansion.
+	flatten int
+
+	// Appends columns to schemas for rows having more fields.
+	dynamic bool
+}
+
+// Returns the ingestion settings of n, creates one if it has none yet.
+func (n *Node) ingestion() *ingestion {
+	if n.ingest == nil {
+		n.ingest = &ingestion{}
+	}
+	return n.ingest
+}
+
+// Converts a field into its string representation. A nil receiver is the same as MustToString().
+func (in *ingestion) toString(a interface{}) string {
+	if in == nil {
+		return MustToString(a)
+	}
+	return in.cache.toString(a)
+}
+
+// Cleans up a string representation. A nil receiver returns s as is.
+func (in *ingestion) clean(s string) string {
+	if in == nil {
+		return s
+	}
+	return in.sanitize.apply(s)
+}
+
+// Returns fields with slices and structs expanded. A nil receiver returns a as is.
+func (in *ingestion) flattened(a []interface{}) []interface{} {
+	if in == nil || in.flatten <= 0 {
+		return a
+	}
+	fields := make([]interface{}, 0, len(a))
+	for _, v := range a {
+		fields = appendFlattened(fields, v, in.flatten)
+	}
+	return fields
+}
+
+func withRowIngestion(in *ingestion) RowOpt {
+	return func(r *Row) {
+		r.ingest = in
+	}
+}
+
+// To memoize String() of fmt.Stringer fields pushed by Push() to the node and its descendants.
+// Helps when expensive Stringers, e.g. shared label objects, appear repeatedly across rows.
+//
+// Values are keyed by themselves, pointers by their addresses. So String() must return the same
+// string for the same key during the lifetime of the node.
+func WithStringCache() NodeOpt {
+	return func(n *Node) {
+		n.ingestion().cache = stringCache{}
+	}
+}
+
+// Memoizes MustToString() of fmt.Stringer values. A nil cache memoizes nothing.
+type stringCache map[interface{}]string
+
+func (c stringCache) toString(a interface{}) string {
+	if _, ok := a.(fmt.Stringer); !ok || c == nil || !isHashable(reflect.TypeOf(a)) {
+		return MustToString(a)
+	}
+	if s, ok := c[a]; ok {
+		return s
+	}
+	s := MustToString(a)
+	c[a] = s
+	return s
+}
+
+// Reports whether values of t can be map keys without panics. Comparable types holding
+// interfaces aren't, since the dynamic value might not be comparable.
+func isHashable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Interface:
+		return false
+	case reflect.Array:
+		return isHashable(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if !isHashable(t.Field(i).Type) {
+				return false
+			}
+		}
+		return true
+	default:
+		return t.Comparable()
+	}
+}
+
+// To expand fields pushed by Push() to the node and its descendants that are slices, arrays or
+// structs into multiple columns, one per element or exported field, instead of rendering the whole
+// value. Nested ones are expanded up to depth levels. Byte slices, maps, fmt.Stringer values and
+// time.Time stay single fields. Struct fields tagged `pprint:"-"` are skipped.
+//
+// Values of different lengths expand into different numbers of columns, which are then shrinked
+// or enlarged to fit the schema as usual.
+func WithFlatten(depth int) NodeOpt {
+	return func(n *Node) {
+		n.ingestion().flatten = depth
+	}
+}
+
+// Appends v to fields, expanded up to depth levels.
+func appendFlattened(fields []interface{}, v interface{}, depth int) []interface{} {
+	if depth <= 0 || v == nil {
+		return append(fields, v)
+	}
+	if _, ok := v.(fmt.Stringer); ok {
+		return append(fields, v)
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() && rv.Elem().Kind() == reflect.Struct {
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+		for i := 0; i < rv.Len(); i++ {
+			fields = appendFlattened(fields, rv.Index(i).Interface(), depth-1)
+		}
+		return fields
+	case reflect.Struct:
+		if _, ok := rv.Interface().(time.Time); ok {
+			break
+		}
+		for i := 0; i < rv.NumField(); i++ {
+			if f := rv.Type().Field(i); f.PkgPath != "" || f.Tag.Get("pprint") == "-" {
+				continue
+			}
+			fields = appendFlattened(fields, rv.Field(i).Interface(), depth-1)
+		}
+		return fields
+	}
+	return append(fields, v)
+}
+
+// To append auto-width columns to the schema when a row pushed by Push() to the node or its
+// descendants has more fields than the schema, instead of dropping the extra fields. Rows pushed
+// before get blanks in the new columns. It suits aggregating records with optional fields.
+func WithDynamicColumns() NodeOpt {
+	return func(n *Node) {
+		n.ingestion().dynamic = true
+	}
+}
+
+// Appends columns to the schema fields pushed to n will get, if they are more than its columns and
+// columns are dynamic. The rows in the tree of n sharing the schema get blanks in the new columns.
+// The tree gets a copy of the schema, other trees sharing it keep it as it was.
+func (n *Node) growSchema(fields int) {
+	s := n.schema
+	if s == nil && n.parent != nil {
+		s = n.parent.schema
+	}
+	if n.ingest == nil || !n.ingest.dynamic || s == nil || s.frozen || fields <= s.count {
+		return
+	}
+
+	grown := fields - s.count
+	wider := s.Clone()
+	for i := 0; i < grown; i++ {
+		wider.cols = append(wider.cols, NewColumn())
+	}
+	wider.count = fields
+
+	n.rebase(s, wider, func(r *Row) {
+		r.fields = append(append([]interface{}(nil), r.fields...), make([]interface{}, grown)...)
+		for i := 0; i < grown; i++ {
+			r.fmtArgs = append(r.fmtArgs, "")
+		}
+	})
+}
+
+// To clean up the string representations of fields pushed by Push() to the node and its
+// descendants before they are stored, protecting rendering and exports from hostile or corrupted
+// data. Raw values are kept as is for sorting. Sanitizing options are:
+//
+// WithUTF8Replacement(string): replacement of invalid UTF-8 sequences. Defaults to "\uFFFD".
+//
+// WithNormalizer(func(string) string): normalizes valid strings, e.g. norm.NFC.String.
+//
+// WithTabExpansion(int): expands tabs to spaces up to the next tab stop. Defaults to no expansion.
+//
+// WithControlEscaping(): escapes non-printable control characters, e.g. "\x1b". Defaults to none.
+//
+// WithMaxBytes(int): caps the byte length at a rune boundary. Defaults to no limit.
+func WithSanitizing(opts ...SanitizeOpt) NodeOpt {
+	return func(n *Node) {
+		s := &sanitizer{replacement: "\uFFFD"}
+		for _, opt := range opts {
+			opt(s)
+		}
+		n.ingestion().sanitize = s
+	}
+}
+
+type sanitizer struct {
+	replacement string
+	normalize   func(string) string
+	tabWidth    int
+	escape      bool
+	maxBytes    int
+}
+
+// Returns the cleaned up s. A nil receiver returns s as is.
+func (s *sanitizer) apply(str string) string {
+	if s == nil {
+		return str
+	}
+
+	if !utf8.ValidString(str) {
+		str = strings.ToValidUTF8(str, s.replacement)
+	}
+	if s.normalize != nil {
+		str = s.normalize(str)
+	}
+	if s.tabWidth > 0 {
+		str = expandTabs(str, s.tabWidth)
+	}
+	if s.escape {
+		str = escapeControls(str)
+	}
+	if s.maxBytes > 0 && len(str) > s.maxBytes {
+		i := s.maxBytes
+		for i > 0 && !utf8.RuneStart(str[i]) {
+			i--
+		}
+		str = str[:i]
+	}
+	return str
+}
+
+type SanitizeOpt func(*sanitizer)
+
+// Replace invalid UTF-8 sequences with r. Defaults to "\uFFFD", an empty string drops them.
+func WithUTF8Replacement(r string) SanitizeOpt {
+	return func(s *sanitizer) {
+		s.replacement = r
+	}
+}
+
+// Normalize strings after invalid UTF-8 sequences are replaced. It's usually a Unicode
+// normalization form, e.g. norm.NFC.String from golang.org/x/text/unicode/norm.
+func WithNormalizer(fn func(string) string) SanitizeOpt {
+	return func(s *sanitizer) {
+		s.normalize = fn
+	}
+}
+
+// Expand tabs to spaces up to the next multiple of n display columns, so cells keep their
+// alignment. n <= 0 means no expansion.
+func WithTabExpansion(n int) SanitizeOpt {
+	return func(s *sanitizer) {
+		s.tabWidth = n
+	}
+}
+
+// Escape non-printable control characters the way Go quotes them, e.g. "\x1b" or "\n", since the
+// terminal would interpret them. Tabs are kept if WithTabExpansion() is set. Note ANSI styles in
+// values get escaped as well.
+func WithControlEscaping() SanitizeOpt {
+	return func(s *sanitizer) {
+		s.escape = true
+	}
+}
+
+// Cap the byte length of strings, they are cut at a rune boundary. n <= 0 means no limit.
+func WithMaxBytes(n int) SanitizeOpt {
+	return func(s *sanitizer) {
+		s.maxBytes = n
+	}
+}
+
+func expandTabs(s string, n int) string {
+	if !strings.ContainsRune(s, '\t') {
+		return s
+	}
+	var (
+		b   strings.Builder
+		col int
+	)
+	for _, r := range s {
+		switch r {
+		case '\t':
+			pad := n - col%n
+			b.WriteString(strings.Repeat(" ", pad))
+			col += pad
+		case '\n':
+			b.WriteRune(r)
+			col = 0
+		default:
+			b.WriteRune(r)
+			col += runeWidth(r)
+		}
+	}
+	return b.String()
+}
+
+func escapeControls(s string) string {
+	if strings.IndexFunc(s, unicode.IsControl) < 0 {
+		return s
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if unicode.IsControl(r) {
+			q := strconv.QuoteRune(r)
+			b.WriteString(q[1 : len(q)-1])
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
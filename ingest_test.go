@@ -0,0 +1,195 @@
+package pprint
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingLabel struct {
+	name  string
+	calls *int
+}
+
+func (l countingLabel) String() string {
+	*l.calls += 1
+	return l.name
+}
+
+type anyLabel struct {
+	v interface{}
+}
+
+func (l anyLabel) String() string {
+	return fmt.Sprint(l.v)
+}
+
+func TestNodeWithStringCache(t *testing.T) {
+	assert := assert.New(t)
+
+	calls := 0
+	var (
+		a = countingLabel{"a", &calls}
+		b = &countingLabel{"b", &calls}
+	)
+
+	n := NewNode(WithStringCache())
+	m, _ := n.Push(a, b)
+	n.Push(a, b)
+	m.Push(a, b)
+	m.Push(anyLabel{[]int{1}}, anyLabel{nil})
+	assert.Equal(2, calls, "each key is converted once, including descendants")
+	assert.Equal("  a     b\n  a     b\n[1] <nil>\n  a     b\n", n.String())
+
+	calls = 0
+	n = NewNode()
+	n.Push(a, b)
+	n.Push(a, b)
+	assert.Equal(4, calls, "no cache by default")
+}
+
+func TestNodeWithSanitizing(t *testing.T) {
+	upper := func(s string) string { return strings.ToUpper(s) }
+
+	tests := map[string]struct {
+		opts     []SanitizeOpt
+		in       []interface{}
+		expected []interface{}
+	}{
+		"invalid utf8": {
+			nil,
+			[]interface{}{"a\xffb", []byte("\xc3"), 1},
+			[]interface{}{"a\uFFFDb", "\uFFFD", "1"},
+		},
+		"drop invalid utf8": {
+			[]SanitizeOpt{WithUTF8Replacement("")},
+			[]interface{}{"a\xffb"},
+			[]interface{}{"ab"},
+		},
+		"normalize": {
+			[]SanitizeOpt{WithNormalizer(upper)},
+			[]interface{}{"a\xffb"},
+			[]interface{}{"A\uFFFDB"},
+		},
+		"expand tabs": {
+			[]SanitizeOpt{WithTabExpansion(4)},
+			[]interface{}{"a\tb", "日\t\tc", "\t"},
+			[]interface{}{"a   b", "日      c", "    "},
+		},
+		"escape controls": {
+			[]SanitizeOpt{WithControlEscaping()},
+			[]interface{}{"\x1b[31mred", "a\tb\nc\x00", "日本"},
+			[]interface{}{"\\x1b[31mred", "a\\tb\\nc\\x00", "日本"},
+		},
+		"expand tabs then escape": {
+			[]SanitizeOpt{WithControlEscaping(), WithTabExpansion(2)},
+			[]interface{}{"a\tb\x7f"},
+			[]interface{}{"a b\\x7f"},
+		},
+		"max bytes at rune boundary": {
+			[]SanitizeOpt{WithMaxBytes(4)},
+			[]interface{}{"abcdef", "a日本", "ab"},
+			[]interface{}{"abcd", "a日", "ab"},
+		},
+	}
+
+	for name, test := range tests {
+		n := NewNode(WithSanitizing(test.opts...))
+		m, _ := n.Push(test.in...)
+		c, _ := m.Push(test.in...)
+		assert.Equal(t, test.expected, m.Row().FmtArgs(), name)
+		assert.Equal(t, test.expected, c.Row().FmtArgs(), name+": descendants")
+		assert.Equal(t, test.in, m.Row().fields, name+": raw values are kept")
+	}
+}
+
+func TestNodeWithFlatten(t *testing.T) {
+	type inner struct {
+		X, Y int
+	}
+	type outer struct {
+		Name   string
+		Point  inner
+		Tags   []string
+		Secret string `pprint:"-"`
+		hidden int
+		When   time.Time
+	}
+	when := time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+	v := outer{"p", inner{1, 2}, []string{"a", "b"}, "s", 0, when}
+
+	tests := map[string]struct {
+		depth    int
+		in       []interface{}
+		expected []interface{}
+	}{
+		"no flattening": {
+			0,
+			[]interface{}{[]int{1, 2}},
+			[]interface{}{[]int{1, 2}},
+		},
+		"one level": {
+			1,
+			[]interface{}{0, v},
+			[]interface{}{0, "p", inner{1, 2}, []string{"a", "b"}, when},
+		},
+		"nested": {
+			2,
+			[]interface{}{&v, [2]int{3, 4}},
+			[]interface{}{"p", 1, 2, "a", "b", when, 3, 4},
+		},
+		"kept as is": {
+			3,
+			[]interface{}{[]byte("ab"), map[string]int{"a": 1}, anyLabel{"l"}, nil, (*outer)(nil)},
+			[]interface{}{[]byte("ab"), map[string]int{"a": 1}, anyLabel{"l"}, nil, (*outer)(nil)},
+		},
+	}
+
+	for name, test := range tests {
+		n := NewNode(WithFlatten(test.depth))
+		m, _ := n.Push(test.in...)
+		c, _ := m.Push(test.in...)
+		assert.Equal(t, test.expected, m.Row().fields, name)
+		assert.Equal(t, test.expected, c.Row().fields, name+": descendants")
+	}
+}
+
+func TestNodeWithDynamicColumns(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode(WithDynamicColumns())
+	a, _ := n.Push("alice", 30)
+	b, _ := a.Push("bob")
+	n.Push("carol", 5, "admin", "x")
+	c, _ := a.Push("dave", 1, "guest")
+
+	assert.Equal(4, n.Schema().count)
+	assert.Same(n.Schema(), c.Row().Schema())
+	assert.Equal([]interface{}{"alice", 30, nil, nil}, a.Row().fields)
+	assert.Equal([]interface{}{"bob", "", "", ""}, b.Row().FmtArgs())
+	assert.Equal(
+		"alice 30        \n"+
+			"  bob           \n"+
+			" dave  1 guest  \n"+
+			"carol  5 admin x\n",
+		n.String(),
+	)
+
+	m := NewNode()
+	d, _ := m.Push("x")
+	d.Push("y", 2)
+	assert.Equal(1, m.Schema().count, "columns aren't dynamic by default")
+
+	o := NewNode(WithDynamicColumns())
+	o.Push("x", 1)
+	shared := NewNode(WithSchema(o.Schema()))
+	shared.Push("y", 2)
+	o.Push("z", 3, 4)
+	assert.Equal(3, o.Schema().count)
+	assert.Equal(2, shared.Schema().count, "other trees keep the schema")
+	assert.Equal("y 2\n", shared.String())
+	assert.NoError(o.Validate())
+}
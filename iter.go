@@ -0,0 +1,33 @@
+package pprint
+
+import "iter"
+
+// Returns an iterator over receiver's descendants in the same order as Walk(). Breaking out of the
+// range loop stops the traversal:
+//
+//	for c := range n.All() {
+//	  if c.Get("error") != nil {
+//	    break
+//	  }
+//	}
+func (n *Node) All() iter.Seq[*Node] {
+	return func(yield func(*Node) bool) {
+		n.WalkControl(func(c *Node) WalkAction {
+			if !yield(c) {
+				return WalkStop
+			}
+			return WalkContinue
+		})
+	}
+}
+
+// Returns an iterator over receiver's children, along with their indexes.
+func (n *Node) ChildrenSeq() iter.Seq2[int, *Node] {
+	return func(yield func(int, *Node) bool) {
+		for i, c := range n.nodes {
+			if !yield(i, c) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package pprint
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeIterators(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode()
+	a, _ := n.Push("a")
+	a.Push("a1")
+	n.Push("b")
+
+	var seen []interface{}
+	for c := range n.All() {
+		seen = append(seen, c.Row().fields[0])
+	}
+	assert.Equal([]interface{}{"a", "a1", "b"}, seen)
+
+	seen = seen[:0]
+	for c := range n.All() {
+		if seen = append(seen, c.Row().fields[0]); len(seen) == 2 {
+			break
+		}
+	}
+	assert.Equal([]interface{}{"a", "a1"}, seen, "breaks early")
+	assert.Len(slices.Collect(n.All()), 3)
+
+	var idx []int
+	for i, c := range n.ChildrenSeq() {
+		assert.Same(n, c.Parent())
+		idx = append(idx, i)
+	}
+	assert.Equal([]int{0, 1}, idx)
+	for range n.ChildrenSeq() {
+		break
+	}
+}
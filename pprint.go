@@ -2,13 +2,17 @@
 package pprint
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
 	"reflect"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -29,6 +33,25 @@ type Node struct {
 	// All input data goes here. The schema must be identical with parent's schema.
 	// For root nodes, this field could be nil.
 	row *Row
+
+	// Shared with the rows pushed to the node and its descendants. Could be nil.
+	ingest *ingestion
+
+	// Renders the row of the node instead of Printing if not nil.
+	renderer RowRenderer
+
+	// Caps the children, evict picks the ones to drop when it's exceeded. 0 means no cap.
+	maxChildren int
+	evict       EvictPolicy
+
+	// Order of pushes across trees, the lower the older.
+	seq uint64
+
+	// Looked up by the parent's Child(), could be empty.
+	name string
+
+	// Arbitrary values set by Set(), could be nil.
+	meta map[string]interface{}
 }
 
 // Creates a node to store the inputs and makes it a child of the current receiver.
@@ -45,9 +68,18 @@ type Node struct {
 //
 // The column (fields) amount of the input doesn't have to be the same with receiver's.
 // It will be enlarged (with empty string) or shrinked to fit the receiver's schema.
+//
+// A single RowMarshaler input is replaced by the fields it returns.
 func (n *Node) Push(a ...interface{}) (newNode *Node, err error) {
 	var opts []RowOpt
 
+	if len(a) == 1 {
+		if m, ok := a[0].(RowMarshaler); ok {
+			a = m.PprintRow()
+		}
+	}
+	a = n.ingest.flattened(a)
+	n.growSchema(len(a))
 	switch n.schema == nil {
 	case true:
 		// Receiver has no children, it's ok to accept any new nodes,
@@ -61,9 +93,53 @@ func (n *Node) Push(a ...interface{}) (newNode *Node, err error) {
 		// Receiver has children, we new a Row with identical schema to enforce inheritance.
 		opts = []RowOpt{WithRowSchema(n.schema), WithRowData(a...)}
 	}
+	if n.ingest != nil {
+		opts = append(opts, withRowIngestion(n.ingest))
+	}
 	return n.PushRow(NewRow(opts...))
 }
 
+// Pushes rows in one call, the same way as Push() for each of them, e.g. to load thousands of
+// records. The children of receiver are grown once beforehand, and the widths are measured in a
+// single pass once the rows are pushed. Returns the created nodes, and any error encountered, the
+// rows before it are pushed then.
+func (n *Node) PushAll(rows [][]interface{}) ([]*Node, error) {
+	if free := cap(n.nodes) - len(n.nodes); free < len(rows) {
+		n.nodes = append(make(nodes, 0, len(n.nodes)+len(rows)), n.nodes...)
+	}
+
+	var (
+		out     = make([]*Node, 0, len(rows))
+		batched []*ColumnSchema
+	)
+	defer func() {
+		for _, s := range batched {
+			s.batching = false
+		}
+		for _, c := range out {
+			c.row.measure()
+		}
+	}()
+	for _, a := range rows {
+		// The schema rows get, replaced if dynamic columns grow it.
+		s := n.schema
+		if s == nil && n.parent != nil {
+			s = n.parent.schema
+		}
+		if s != nil && !s.batching {
+			s.batching = true
+			batched = append(batched, s)
+		}
+
+		c, err := n.Push(a...)
+		if err != nil {
+			return out, err
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
 // Accepts a customized Row. Returns a pointer to the created node and any error encountered.
 func (n *Node) PushRow(r *Row) (newNode *Node, err error) {
 	return n.PushNode(NewNode(WithRow(r)))
@@ -107,549 +183,2711 @@ func (n *Node) PushNode(in *Node) (inMutated *Node, err error) {
 			// B is a tree root without Row instance, gives it an empty Row to merge.
 			in.row = NewRow(WithRowSchema(n.schema))
 		case false:
-			if in.Row().Schema() != n.schema {
-				return nil, fmt.Errorf("PushNode: row of the incoming node doesn't match my node schema")
+			if err := n.schema.Validate(in.Row()); err != nil {
+				return nil, fmt.Errorf("PushNode: row of the incoming node doesn't match my node schema: %v", err)
 			}
 			// Same schema is allowed
 		}
 	}
 
 	in.parent = n
+	in.seq = atomic.AddUint64(&pushes, 1)
 	n.nodes = append(n.nodes, in)
+	if in.ingest == nil {
+		in.ingest = n.ingest
+	}
+	n.enforceMaxChildren()
 
 	return in, err
 }
 
+// Same as Push() but inserts the created node at index i of receiver's children, e.g. to pin rows
+// at the top with i = 0. i could range from 0 to NodesCount(). Returns any error encountered.
+func (n *Node) PushAt(i int, a ...interface{}) (newNode *Node, err error) {
+	if i < 0 || i > len(n.nodes) {
+		return nil, fmt.Errorf("PushAt: index %d out of range", i)
+	}
+	newNode, err = n.Push(a...)
+	if err != nil {
+		return nil, err
+	}
+	n.place(newNode, i)
+	return newNode, nil
+}
+
+// Same as PushNode() but inserts the incoming node at index i of receiver's children. i could range
+// from 0 to NodesCount(). Returns any error encountered.
+func (n *Node) PushNodeAt(i int, in *Node) (inMutated *Node, err error) {
+	if i < 0 || i > len(n.nodes) {
+		return nil, fmt.Errorf("PushNodeAt: index %d out of range", i)
+	}
+	inMutated, err = n.PushNode(in)
+	if err != nil {
+		return nil, err
+	}
+	n.place(inMutated, i)
+	return inMutated, nil
+}
+
+// Moves child c to index i of receiver's children, or the last index if i is beyond it.
+// Does nothing if c isn't a child, e.g. evicted.
+func (n *Node) place(c *Node, i int) {
+	for k, m := range n.nodes {
+		if m != c {
+			continue
+		}
+		if i >= len(n.nodes) {
+			i = len(n.nodes) - 1
+		}
+		copy(n.nodes[k:], n.nodes[k+1:])
+		copy(n.nodes[i+1:], n.nodes[i:len(n.nodes)-1])
+		n.nodes[i] = c
+		return
+	}
+}
+
 // Sort receiver's child nodes (that contain rows) on the given column of that node's row.
 // Accepts a column index starting from 0. Returns any error encountered.
 //
 // It uses stable sort to compare the raw value of the specified column field.
-// Sort on values with non identical type returns an error.
+// Sort on values with non identical type returns an error, nil values included unless
+// WithNilsFirst() or WithNilsLast() is given.
 // Sort on values with no type comparators returns an error.
+// WithStringFallback() compares their string representations instead of both errors.
 //
-// Note that it doesn't sort descendants.
+// Note that it doesn't sort descendants unless WithRecursive() is given.
 //
 // Sorting options are:
 //
 // WithDescending(): default is ascending.
 //
-// WithCmpMatchers(...func(a interface{}) CmpFn): to sort more types. Builtins: int, string and time.Time.
+// WithRecursive(): to sort the children of every descendant as well.
+//
+// WithSortKey(func(*Row) interface{}): to sort on keys derived from rows, col is ignored.
+//
+// WithNodeSortKey(func(*Node) interface{}): to sort on keys derived from nodes, e.g. their metadata.
+//
+// WithCaseInsensitive(): to sort strings ignoring case.
+//
+// WithNilsFirst(), WithNilsLast(): to place nil values at one end and sort the others.
+//
+// WithStringFallback(): to sort mixed or unknown types on MustToString() of the values.
+//
+// WithLimit(n): to keep only the first n children after sorting, see Limit().
+//
+// WithCollation(func(a, b string) int): to sort strings by a collation.
+//
+// WithCmpMatchers(...func(a interface{}) CmpFn): to sort more types. See MatchCmp() for the builtins.
 func (n *Node) Sort(col int, opts ...SortOpt) error {
-	if n.schema == nil || col < 0 || col >= n.schema.count {
+	probe := &sortable{}
+	for _, opt := range opts {
+		opt(probe)
+	}
+	if probe.key == nil && (n.schema == nil || col < 0 || col >= n.schema.count) {
 		return fmt.Errorf("Sort: column %d doesn't exist", col)
 	}
-	if n.NodesCount() < 2 {
-		return nil
+	if n.NodesCount() >= 2 {
+		nodes, err := createSortableOn(col, []*Node(n.nodes), opts...)
+		if err != nil {
+			return err
+		}
+		sort.Stable(nodes)
 	}
 
-	nodes, err := createSortableOn(col, []*Node(n.nodes), opts...)
-	if err != nil {
-		return err
+	if probe.limited {
+		n.Limit(probe.limit)
+	}
+	if !probe.recursive {
+		return nil
+	}
+	for _, c := range n.nodes {
+		if c.NodesCount() == 0 {
+			continue
+		}
+		if err := c.Sort(col, opts...); err != nil {
+			return err
+		}
 	}
-
-	sort.Stable(nodes)
 	return nil
 }
 
-// Traverses receiver's descendants.
-func (n *Node) Walk(fn func(*Node)) {
-	n.EachNode(func(c *Node) {
-		fn(c)
-		c.Walk(fn)
-	})
+// Same as Sort() on the column named name, see WithColumnName(). Returns any error encountered.
+func (n *Node) SortByName(name string, opts ...SortOpt) error {
+	if n.schema == nil || n.schema.Index(name) < 0 {
+		return fmt.Errorf("SortByName: column %q doesn't exist", name)
+	}
+	return n.Sort(n.schema.Index(name), opts...)
 }
 
-// Traverses receiver's children. Use Walk() to traverse descendants.
-func (n *Node) EachNode(fn func(*Node)) {
-	for _, c := range n.nodes {
-		fn(c)
+// Rewrites the rows of receiver's descendants to schema s, e.g. after a config reload changed the
+// displayed columns. mapping[i] is the old column that column i of s takes its values from,
+// -1 leaves it empty. So columns can be reordered, added or dropped. Returns any error encountered,
+// the tree is untouched then.
+//
+// Only the rows and nodes sharing receiver's schema are migrated, descendants having their own
+// schemas, e.g. pushed by PushNode(), keep them. Raw values are converted again to fill s.
+func (n *Node) MigrateSchema(s *ColumnSchema, mapping []int) error {
+	old := n.schema
+	switch {
+	case old == nil:
+		return fmt.Errorf("MigrateSchema: no schema to migrate")
+	case s == nil:
+		return fmt.Errorf("MigrateSchema: nil schema")
+	case len(mapping) != s.count:
+		return fmt.Errorf("MigrateSchema: %d mappings for %d columns", len(mapping), s.count)
+	}
+	for i, m := range mapping {
+		if m < -1 || m >= old.count {
+			return fmt.Errorf("MigrateSchema: column %d maps to nonexistent column %d", i, m)
+		}
 	}
-}
 
-// Collects each descendant's String() and prints with default options.
-func (n *Node) String() string {
-	var b strings.Builder
-	NewPrinting(WithWriter(&b), WithColSep(" ")).RunNode(n)
-	return b.String()
+	migrate := func(c *Node) {
+		if c.schema == old {
+			c.schema = s
+		}
+		r := c.row
+		if r == nil || r.schema != old {
+			return
+		}
+		fields := make([]interface{}, s.count)
+		for i, m := range mapping {
+			if m >= 0 {
+				fields[i] = r.fields[m]
+			}
+		}
+		r.schema, r.fields = s, fields
+		r.prepare()
+	}
+	migrate(n)
+	n.Walk(migrate)
+	return nil
 }
 
-// Returns receiver's child count.
-func (n *Node) NodesCount() int {
-	return len(n.nodes)
-}
+// Appends a column named name to receiver's schema, whose values are derived from the other fields
+// by fn, e.g. the ratio of two columns. opts describe the column like NewColumn(). The rows of the
+// tree sharing the schema get their values at once, rows created later on push. Values given for
+// the column on push are replaced. Returns any error encountered.
+//
+// fn gets the row with the parsed fields of the other columns, computed columns appended earlier
+// included, and must not modify it.
+//
+// The tree gets a copy of the schema having the column appended, other trees sharing the schema,
+// e.g. by WithSchema(), keep it as it was.
+func (n *Node) AddComputedColumn(name string, fn func(row *Row) interface{}, opts ...ColumnOpt) error {
+	s := n.schema
+	switch {
+	case s == nil:
+		return fmt.Errorf("AddComputedColumn: no schema to add to")
+	case fn == nil:
+		return fmt.Errorf("AddComputedColumn: nil function")
+	case s.frozen:
+		return fmt.Errorf("AddComputedColumn: schema is frozen")
+	}
 
-// Returns receiver's parent.
-func (n *Node) Parent() *Node {
-	return n.parent
+	c := NewColumn(append(append([]ColumnOpt(nil), opts...), WithColumnName(name))...)
+	c.compute = fn
+	computed := s.Clone()
+	computed.cols = append(computed.cols, c)
+	computed.count++
+	n.rebase(s, computed, (*Row).prepare)
+	return nil
 }
 
-// Returns the attached Row instance of current receiver.
-func (n *Node) Row() *Row {
-	return n.row
-}
+// Appends c to receiver's schema and fills it for the rows of receiver and its descendants sharing
+// the schema. values is either a []interface{} holding a value per row in the order of Walk(), or a
+// func(*Node) interface{} returning the value of the row of a node. Other rows of the tree sharing
+// the schema get nils. Returns any error encountered, the tree is untouched then.
+//
+// The tree gets a copy of the schema having c appended, other trees sharing the schema, e.g. by
+// WithSchema(), keep it as it was.
+func (n *Node) AppendColumn(c Column, values interface{}) error {
+	s := n.schema
+	switch {
+	case s == nil:
+		return fmt.Errorf("AppendColumn: no schema to append to")
+	case s.frozen:
+		return fmt.Errorf("AppendColumn: schema is frozen")
+	}
 
-// Returns the schema instance of current receiver.
-func (n *Node) Schema() *ColumnSchema {
-	return n.schema
-}
+	var ns []*Node
+	collect := func(c *Node) {
+		if r := c.row; r != nil && r.schema == s {
+			ns = append(ns, c)
+		}
+	}
+	collect(n)
+	n.Walk(collect)
+
+	vs := make([]interface{}, len(ns))
+	switch v := values.(type) {
+	case []interface{}:
+		if len(v) != len(ns) {
+			return fmt.Errorf("AppendColumn: %d values for %d rows", len(v), len(ns))
+		}
+		copy(vs, v)
+	case func(*Node) interface{}:
+		for i, c := range ns {
+			vs[i] = v(c)
+		}
+	default:
+		return fmt.Errorf("AppendColumn: %T isn't values or a function", values)
+	}
 
-// Returns true if receiver has parent.
-func (n *Node) IsNotRoot() bool {
-	return n.Parent() != nil
+	byRow := make(map[*Row]interface{}, len(ns))
+	for i, c := range ns {
+		byRow[c.row] = vs[i]
+	}
+
+	appended := s.Clone()
+	appended.cols = append(appended.cols, c)
+	appended.count++
+	n.rebase(s, appended, func(r *Row) {
+		r.fields = append(append([]interface{}(nil), r.fields...), byRow[r])
+		r.prepare()
+	})
+	return nil
 }
 
-// Returns a pointer to a Node instance. Node options are:
-//
-// WithRow(*Row): creates a node with provided Row instance.
+// Returns a new tree with rows and columns of receiver's descendants swapped, for small tables with
+// many columns and few rows. Column i of the descendants becomes the row i, in the order of Walk().
+// Cells keep their string representations, widths are measured on the transposed layout.
 //
-// WithSchema(*ColumnSchema): to inherit the schema from an existing row or node to be applied to all of its children.
-//
-// WithColumns(...Column): to create a node with provided column schema to be applied to all of its children.
-func NewNode(opts ...NodeOpt) *Node {
-	n := &Node{}
-	for _, opt := range opts {
-		opt(n)
+// Only the descendants sharing receiver's schema are transposed.
+func (n *Node) Transpose() *Node {
+	out := NewNode()
+	if n.schema == nil {
+		return out
 	}
-	return n
-}
-
-type NodeOpt func(*Node)
 
-// To inherit the schema from an existing row or node to be applied to all of its children.
-func WithSchema(s *ColumnSchema) NodeOpt {
-	return func(n *Node) {
-		n.schema = s
+	rows := make([][]interface{}, n.schema.count)
+	n.Walk(func(c *Node) {
+		if r := c.Row(); r != nil && r.schema == n.schema {
+			for i, a := range r.fmtArgs {
+				rows[i] = append(rows[i], a)
+			}
+		}
+	})
+	for _, r := range rows {
+		out.Push(r...)
 	}
+	return out
 }
 
-// To create a node with provided column schema to be applied to all of its children.
-func WithColumns(c ...Column) NodeOpt {
-	return func(n *Node) {
-		n.schema = NewSchema(c...)
+// Removes child from receiver's children and detaches it, so that it could be pushed to another
+// node. Returns an error if child isn't a child of receiver.
+func (n *Node) Remove(child *Node) error {
+	for i, c := range n.nodes {
+		if c == child {
+			n.nodes = append(n.nodes[:i], n.nodes[i+1:]...)
+			child.parent = nil
+			return nil
+		}
 	}
+	return fmt.Errorf("Remove: not a child of the node")
 }
 
-// Creates a node with provided Row instance.
-func WithRow(r *Row) NodeOpt {
-	return func(n *Node) {
-		n.row = r
+// Removes receiver from its parent, if any. Receiver keeps its row and descendants.
+func (n *Node) Detach() {
+	if n.parent != nil {
+		n.parent.Remove(n)
 	}
 }
 
-// Stores alignment and width.
-type Column struct {
-	width int
-	pad   struct {
-		fixed bool
-		right bool
+// Detaches receiver from its parent and pushes it to p with PushNode(), which validates the schemas
+// the same way. Returns any error encountered, receiver stays where it was then. Moving a node under
+// itself or its descendants is an error.
+func (n *Node) MoveTo(p *Node) error {
+	if p == nil {
+		return fmt.Errorf("MoveTo: nil parent")
+	}
+	for a := p; a != nil; a = a.parent {
+		if a == n {
+			return fmt.Errorf("MoveTo: can't move a node under itself")
+		}
 	}
-}
 
-// Turns current column into a format string, e.g.: "%3s", "%-5s".
-func (c Column) String() string {
-	if s := strconv.FormatInt(int64(c.width), 10); c.pad.right {
-		return "%-" + s + "s"
-	} else {
-		return "%" + s + "s"
+	old, i := n.parent, 0
+	if old != nil {
+		for i < len(old.nodes) && old.nodes[i] != n {
+			i++
+		}
+		n.Detach()
+	}
+	if _, err := p.PushNode(n); err != nil {
+		if old != nil {
+			old.nodes = append(old.nodes, nil)
+			copy(old.nodes[i+1:], old.nodes[i:])
+			old.nodes[i], n.parent = n, old
+		}
+		return err
 	}
+	return nil
 }
 
-// Returns a Column instance. Column options are:
-//
-// WithWidth(int): by default all columns are auto-width. Set to fix-width. WithWidth(20) is translated to "%20s".
-//
-// WithLeftAlignment(): set to pad to the right. For example: WithWidth(20), WithLeftAlignment() = "%-20s".
-func NewColumn(opts ...ColumnOpt) Column {
-	c := Column{}
-	for _, opt := range opts {
-		opt(&c)
+// Replaces receiver's row with r, and measures the widths of the schema again from the rows of the
+// tree sharing it, so that they shrink as well as grow. r must be of the schema of receiver's parent.
+// Returns any error encountered.
+func (n *Node) SetRow(r *Row) error {
+	switch {
+	case r == nil:
+		return fmt.Errorf("SetRow: nil row")
+	case n.parent != nil && r.schema != n.parent.schema:
+		return fmt.Errorf("SetRow: row doesn't match the schema of the parent")
 	}
-	return c
+	n.row = r
+	n.remeasure(r.schema)
+	return nil
 }
 
-type ColumnOpt func(*Column)
+// Measures the auto widths of the schemas of receiver's tree again from its current rows, so that
+// they shrink as well as grow, e.g. after rows are removed by Remove() or Limit(), since widths only
+// grow as rows are added. Fixed widths, frozen schemas and the ones of WidthFixed are kept.
+func (n *Node) RecalculateWidths() {
+	root := n
+	for root.parent != nil {
+		root = root.parent
+	}
 
-// By default all columns are auto-width. Set to fix-width. WithWidth(20) is translated to "%20s".
-func WithWidth(w int) ColumnOpt {
-	return func(c *Column) {
-		if w < 0 {
-			w = 0
+	seen := map[*ColumnSchema]bool{}
+	reset := func(c *Node) {
+		if r := c.row; r != nil && !seen[r.schema] {
+			seen[r.schema] = true
+			r.schema.resetWidths()
 		}
-		c.width = w
-		c.pad.fixed = true
 	}
-}
+	reset(root)
+	root.Walk(reset)
 
-// Set to pad to the right. For example: WithWidth(20), WithLeftAlignment() = "%-20s".
-func WithLeftAlignment() ColumnOpt {
-	return func(c *Column) {
-		c.pad.right = true
+	measure := func(c *Node) {
+		if r := c.row; r != nil {
+			r.measure()
+		}
 	}
+	measure(root)
+	root.Walk(measure)
 }
 
-// Defines how many columns in a row and their corresponding Column data.
-type ColumnSchema struct {
-	cols  []Column
-	count int
+// Measures the auto widths of s again from the rows of receiver's tree sharing it.
+func (n *Node) remeasure(s *ColumnSchema) {
+	root := n
+	for root.parent != nil {
+		root = root.parent
+	}
+
+	s.resetWidths()
+	measure := func(c *Node) {
+		if r := c.row; r != nil && r.schema == s {
+			r.measure()
+		}
+	}
+	measure(root)
+	root.Walk(measure)
 }
 
-func NewSchema(c ...Column) *ColumnSchema {
-	return &ColumnSchema{
-		cols:  c,
-		count: len(c),
+// Removes the column i from receiver's schema and from the rows of the tree sharing it, e.g. to
+// exclude unwanted data after ingestion. Cells keep their string representations, the widths of
+// the other columns are kept. Returns any error encountered.
+//
+// Computed columns given by AddComputedColumn() read the fields by their indexes, so the columns
+// before one can't be dropped, it has to be dropped first.
+//
+// The tree gets a copy of the schema without the column, other trees sharing the schema, e.g. by
+// WithSchema(), keep it as it was.
+func (n *Node) DropColumn(i int) error {
+	s := n.schema
+	switch {
+	case s == nil:
+		return fmt.Errorf("DropColumn: no schema to drop from")
+	case i < 0 || i >= s.count:
+		return fmt.Errorf("DropColumn: column %d doesn't exist", i)
+	case s.frozen:
+		return fmt.Errorf("DropColumn: schema is frozen")
 	}
+	for k := i + 1; k < s.count; k++ {
+		if s.cols[k].compute != nil {
+			return fmt.Errorf("DropColumn: computed column %d reads the fields by their indexes", k)
+		}
+	}
+
+	// Full slice expressions copy, leaving the shared and the input slices alone.
+	dropped := s.Clone()
+	dropped.cols = append(dropped.cols[:i:i], dropped.cols[i+1:]...)
+	dropped.count--
+	n.rebase(s, dropped, func(r *Row) {
+		r.fields = append(r.fields[:i:i], r.fields[i+1:]...)
+		r.fmtArgs = append(r.fmtArgs[:i:i], r.fmtArgs[i+1:]...)
+	})
+	return nil
 }
 
-// Creates a column schema instance with N columns. N is the length of input fields.
-func NewSchemaFrom(fields []interface{}) *ColumnSchema {
-	size := len(fields)
+// Moves the nodes and rows of receiver's tree from s to its changed copy to, calling fn on each moved
+// row. Other trees sharing s keep it, so that changing the columns of a tree doesn't break them.
+func (n *Node) rebase(s, to *ColumnSchema, fn func(r *Row)) {
+	root := n
+	for root.parent != nil {
+		root = root.parent
+	}
 
-	return &ColumnSchema{
-		cols:  make([]Column, size),
-		count: size,
+	move := func(c *Node) {
+		if c.schema == s {
+			c.schema = to
+		}
+		if r := c.row; r != nil && r.schema == s {
+			r.schema = to
+			fn(r)
+		}
 	}
+	move(root)
+	root.Walk(move)
 }
 
-// Stores both raw input values and their string representations.
-type Row struct {
-
-	// Defines how many columns this row contains.
-	schema *ColumnSchema
+// Keeps only the first max children of receiver, e.g. to show the top 10 after Sort(). The rest are
+// detached from receiver and returned in order. A negative max keeps all.
+func (n *Node) Limit(max int) []*Node {
+	if max < 0 || len(n.nodes) <= max {
+		return nil
+	}
+	rest := append([]*Node(nil), n.nodes[max:]...)
+	for i := max; i < len(n.nodes); i++ {
+		n.nodes[i].parent, n.nodes[i] = nil, nil
+	}
+	n.nodes = n.nodes[:max]
+	return rest
+}
 
-	// Raw input values, has been shrinked or enlarged by current column schema.
-	fields []interface{}
+// Returns a deep copy of receiver and its descendants, e.g. a snapshot to sort or print differently
+// without touching the original. Rows and metadata are copied, raw values themselves are shared.
+//
+// The copy shares the schemas of receiver unless schemas is true, then each schema is copied once,
+// so that the copy grows its own widths while the nodes sharing a schema still share its copy.
+func (n *Node) Clone(schemas bool) *Node {
+	copies := map[*ColumnSchema]*ColumnSchema{}
+	schemaOf := func(s *ColumnSchema) *ColumnSchema {
+		if !schemas || s == nil {
+			return s
+		}
+		if c, ok := copies[s]; ok {
+			return c
+		}
+		c := s.Clone()
+		copies[s] = c
+		return c
+	}
 
-	// String representations of Row.fields. Used to calculate padding and fmt.Printf().
-	fmtArgs []interface{}
+	var clone func(n *Node) *Node
+	clone = func(n *Node) *Node {
+		c := *n
+		c.parent, c.nodes, c.meta = nil, nil, nil
+		c.schema = schemaOf(n.schema)
+		if r := n.row; r != nil {
+			c.row = &Row{
+				schema:  schemaOf(r.schema),
+				fields:  append([]interface{}(nil), r.fields...),
+				fmtArgs: append([]interface{}(nil), r.fmtArgs...),
+				ingest:  r.ingest,
+			}
+		}
+		for k, v := range n.meta {
+			c.Set(k, v)
+		}
+		for _, m := range n.nodes {
+			mc := clone(m)
+			mc.parent = &c
+			c.nodes = append(c.nodes, mc)
+		}
+		return &c
+	}
+	return clone(n)
 }
 
-// Traverses format strings with String() on each Column instance.
-func (r *Row) EachFmtStr(fn func(string)) {
-	for _, c := range r.schema.cols {
-		fn(c.String())
+// Reverses the order of receiver's children, e.g. for rows arriving in reverse chronological order.
+// The children of every descendant are reversed as well if recursive is true.
+func (n *Node) Reverse(recursive bool) {
+	for i, j := 0, len(n.nodes)-1; i < j; i, j = i+1, j-1 {
+		n.nodes[i], n.nodes[j] = n.nodes[j], n.nodes[i]
+	}
+	if !recursive {
+		return
+	}
+	for _, c := range n.nodes {
+		c.Reverse(true)
 	}
 }
 
-// Returns the string slice that stores the string representations of raw values.
-func (r *Row) FmtArgs() []interface{} {
-	return r.fmtArgs
+// Returns the first of receiver's descendants that match is true for, in the order of Walk(), or nil.
+func (n *Node) Find(match func(*Node) bool) *Node {
+	var found *Node
+	n.WalkControl(func(c *Node) WalkAction {
+		if match(c) {
+			found = c
+			return WalkStop
+		}
+		return WalkContinue
+	})
+	return found
 }
 
-// Returns a string by calling fmt.Fprintf() on fmtStr and fmtArgs.
-func (r *Row) String() string {
+// Returns all of receiver's descendants that match is true for, in the order of Walk().
+func (n *Node) FindAll(match func(*Node) bool) []*Node {
+	var out []*Node
+	n.Walk(func(c *Node) {
+		if match(c) {
+			out = append(out, c)
+		}
+	})
+	return out
+}
+
+// Traverses receiver's descendants in pre-order, a node is visited before its descendants.
+// See WalkLevels() and WalkPostOrder() for other orders.
+func (n *Node) Walk(fn func(*Node)) {
+	n.EachNode(func(c *Node) {
+		fn(c)
+		c.Walk(fn)
+	})
+}
+
+// Traverses receiver's descendants level by level: the children first, then the grandchildren,
+// and so on.
+func (n *Node) WalkLevels(fn func(*Node)) {
+	for level := n.nodes; len(level) > 0; {
+		var next nodes
+		for _, c := range level {
+			fn(c)
+			next = append(next, c.nodes...)
+		}
+		level = next
+	}
+}
+
+// Traverses receiver's descendants bottom-up: the descendants of a node are visited before the
+// node itself, e.g. to sum up subtotals of subtrees.
+func (n *Node) WalkPostOrder(fn func(*Node)) {
+	n.EachNode(func(c *Node) {
+		c.WalkPostOrder(fn)
+		fn(c)
+	})
+}
+
+// Tells WalkControl() how to go on after visiting a node.
+type WalkAction int
+
+const (
+	// Visits the children of the node, then the rest.
+	WalkContinue WalkAction = iota
+
+	// Skips the descendants of the node, and visits the rest.
+	WalkSkipChildren
+
+	// Visits nothing more.
+	WalkStop
+)
+
+// Traverses receiver's descendants in the same order as Walk(), but fn decides whether to go on,
+// e.g. to stop once a match is found or to skip uninteresting subtrees of a large tree.
+// Returns true if fn stopped the traversal.
+func (n *Node) WalkControl(fn func(*Node) WalkAction) (stopped bool) {
+	for _, c := range n.nodes {
+		switch fn(c) {
+		case WalkStop:
+			return true
+		case WalkSkipChildren:
+			continue
+		}
+		if c.WalkControl(fn) {
+			return true
+		}
+	}
+	return false
+}
+
+// Traverses receiver's descendants in the same order as Walk(), until fn returns an error or ctx is
+// done, so that traversals of huge trees could be aborted. Returns the error of fn or ctx.Err().
+func (n *Node) WalkErr(ctx context.Context, fn func(*Node) error) error {
+	var err error
+	n.WalkControl(func(c *Node) WalkAction {
+		if err = ctx.Err(); err == nil {
+			err = fn(c)
+		}
+		if err != nil {
+			return WalkStop
+		}
+		return WalkContinue
+	})
+	return err
+}
+
+// Traverses receiver's children. Use Walk() to traverse descendants.
+func (n *Node) EachNode(fn func(*Node)) {
+	for _, c := range n.nodes {
+		fn(c)
+	}
+}
+
+// Collects each descendant's String() and prints with default options.
+func (n *Node) String() string {
 	var b strings.Builder
-	NewPrinting(WithWriter(&b), WithColSep(" "), WithLineBrk("")).RunRow(r)
+	NewPrinting(WithWriter(&b), WithColSep(" ")).RunNode(n)
 	return b.String()
 }
 
-func (r *Row) Schema() *ColumnSchema {
-	return r.schema
+// Returns a copy of receiver's children, changing it doesn't change the tree.
+func (n *Node) Children() []*Node {
+	return append([]*Node(nil), n.nodes...)
 }
 
-// Initializes a Row instance, on each creation:
-//
-// 1. if no schema found, create a new one based on current data.
-// 2. if with schema, shrink or enlarge input fields to fit to the schema.
-// 3. do string conversion, calculate string length, updates to schema instance.
-func (r *Row) prepare() {
-	switch fs := r.fields; r.schema == nil {
-	case true:
-		// auto creation
-		r.schema = NewSchemaFrom(fs)
-	case false:
-		// shrink or enlarge fields
-		r.fields = resizeSlice(fs, r.schema.count)
+// Returns the child of receiver at index i, or nil if i is out of range.
+func (n *Node) ChildAt(i int) *Node {
+	if i < 0 || i >= len(n.nodes) {
+		return nil
 	}
+	return n.nodes[i]
+}
 
-	r.fmtArgs = make([]interface{}, r.schema.count)
+// Returns receiver's child count.
+func (n *Node) NodesCount() int {
+	return len(n.nodes)
+}
 
-	for i := 0; i < r.schema.count; i++ {
-		r.fmtArgs[i] = MustToString(r.fields[i])
+// Returns receiver's name given by WithName(), could be empty.
+func (n *Node) Name() string {
+	return n.name
+}
 
-		if c := r.schema.cols[i]; !c.pad.fixed {
-			// only updates to those without fixed width
-			w := len(r.fmtArgs[i].(string))
-			if w > c.width {
-				r.schema.cols[i].width = w
-			}
+// Returns the first child of receiver named name, or nil. Lookups can be chained to navigate a tree
+// like directories:
+//   n.Child("users").Child("admins")
+// Calling it on a nil node returns nil.
+func (n *Node) Child(name string) *Node {
+	if n == nil {
+		return nil
+	}
+	for _, c := range n.nodes {
+		if c.name == name {
+			return c
 		}
 	}
+	return nil
 }
 
-// Returns a pointer to a Row instance. Row options are:
+// Attaches value to receiver under key, e.g. to mark a row as an error for WithRowStyler() or
+// WithNodeSortKey(). Values stay with the node when it's pushed to another by PushNode().
+func (n *Node) Set(key string, value interface{}) {
+	if n.meta == nil {
+		n.meta = map[string]interface{}{}
+	}
+	n.meta[key] = value
+}
+
+// Returns the value attached to receiver under key by Set(), or nil.
+func (n *Node) Get(key string) interface{} {
+	return n.meta[key]
+}
+
+// Returns the ancestors of receiver from the root down, receiver included, e.g. for filters to tell
+// where a row lives in the hierarchy.
+func (n *Node) Path() []*Node {
+	path := make([]*Node, n.Depth()+1)
+	for i, p := len(path)-1, n; p != nil; i, p = i-1, p.parent {
+		path[i] = p
+	}
+	return path
+}
+
+// Returns the names of Path() joined by "/", like a directory, e.g. "/users/admins" for a node named
+// "admins" whose parent "users" is a child of an unnamed root.
+func (n *Node) NamePath() string {
+	var names []string
+	for _, p := range n.Path() {
+		names = append(names, p.name)
+	}
+	return strings.Join(names, "/")
+}
+
+// Returns the distance from receiver to the root of its tree, 0 for the root.
+func (n *Node) Depth() int {
+	depth := 0
+	for p := n.parent; p != nil; p = p.parent {
+		depth++
+	}
+	return depth
+}
+
+// Returns the distance from receiver to its deepest descendant, 0 if it has no children.
+func (n *Node) Height() int {
+	height := 0
+	for _, c := range n.nodes {
+		if h := c.Height() + 1; h > height {
+			height = h
+		}
+	}
+	return height
+}
+
+// Returns the count of receiver's descendants. Use NodesCount() to count the children only.
+func (n *Node) Size() int {
+	size := len(n.nodes)
+	for _, c := range n.nodes {
+		size += c.Size()
+	}
+	return size
+}
+
+// Returns receiver's parent.
+func (n *Node) Parent() *Node {
+	return n.parent
+}
+
+// Returns the attached Row instance of current receiver.
+func (n *Node) Row() *Row {
+	return n.row
+}
+
+// Returns the schema instance of current receiver.
+func (n *Node) Schema() *ColumnSchema {
+	return n.schema
+}
+
+// Returns true if receiver has parent.
+func (n *Node) IsNotRoot() bool {
+	return n.Parent() != nil
+}
+
+// Returns a pointer to a Node instance. Node options are:
 //
-// WithRowSchema(*ColumnSchema): to inherit the schema from an existing row or node.
+// WithRow(*Row): creates a node with provided Row instance.
 //
-// WithRowColumns(...Column): to create a row with provided column schema.
+// WithSchema(*ColumnSchema): to inherit the schema from an existing row or node to be applied to all of its children.
 //
-// WithData(...interface{}): set data to the row.
-func NewRow(opts ...RowOpt) *Row {
-	r := &Row{}
+// WithSchemaCopy(*ColumnSchema): to start from a copy of the schema of an existing row or node, isolated from it.
+//
+// WithColumns(...Column): to create a node with provided column schema to be applied to all of its children.
+//
+// WithStringCache(): to memoize String() of fmt.Stringer fields pushed to the node and its descendants.
+//
+// WithRowRenderer(RowRenderer): to render the row of the node by itself.
+//
+// WithSanitizing(...SanitizeOpt): to clean up the string representations of fields pushed to the node and its descendants.
+//
+// WithFlatten(int): to expand slice and struct fields pushed to the node and its descendants into columns.
+//
+// WithDynamicColumns(): to append columns for rows pushed to the node and its descendants having more fields.
+//
+// WithMaxChildren(int, EvictPolicy): to cap the children of the node.
+//
+// WithName(string): to look up the node by name from its parent with Child().
+func NewNode(opts ...NodeOpt) *Node {
+	n := &Node{}
 	for _, opt := range opts {
-		opt(r)
+		opt(n)
 	}
-	r.prepare()
-	return r
+	return n
 }
 
-type RowOpt func(*Row)
+type NodeOpt func(*Node)
 
-// To inherit the schema from an existing row or node.
-func WithRowSchema(s *ColumnSchema) RowOpt {
-	return func(r *Row) {
-		r.schema = s
+// To inherit the schema from an existing row or node to be applied to all of its children.
+func WithSchema(s *ColumnSchema) NodeOpt {
+	return func(n *Node) {
+		n.schema = s
 	}
 }
 
-// To create a row with provided column schema.
-func WithRowColumns(c ...Column) RowOpt {
-	return func(r *Row) {
-		r.schema = NewSchema(c...)
+// To start from a copy of the schema of an existing row or node, so that the rows of the node and
+// of the original don't widen each other's columns. See ColumnSchema.Clone().
+func WithSchemaCopy(s *ColumnSchema) NodeOpt {
+	return func(n *Node) {
+		n.schema = s.Clone()
 	}
 }
 
-// Set data to the row.
-func WithRowData(a ...interface{}) RowOpt {
-	return func(r *Row) {
-		r.fields = a
+// To create a node with provided column schema to be applied to all of its children.
+func WithColumns(c ...Column) NodeOpt {
+	return func(n *Node) {
+		n.schema = NewSchema(c...)
 	}
 }
 
-// Converts anything to a string. The function itself handles the common types including:
-// fmt.Stringer, string, []byte, uint, int and nil. It passes anything else to the fmt.Sprintf
-// to get the string representation of that value. It is used when initializing a Row instance.
-func MustToString(a interface{}) string {
-	var s string
-
-	switch v := a.(type) {
-	case fmt.Stringer:
-		s = v.String()
-	case string:
-		s = v
-	case []byte:
-		s = string(v)
-	case uint:
-		s = strconv.FormatUint(uint64(v), 10)
-	case int:
-		s = strconv.FormatInt(int64(v), 10)
-	case nil:
-	default:
-		s = fmt.Sprintf("%v", v)
+// Creates a node with provided Row instance.
+func WithRow(r *Row) NodeOpt {
+	return func(n *Node) {
+		n.row = r
 	}
+}
 
-	return s
+// Names the node so that its parent could look it up with Child().
+func WithName(name string) NodeOpt {
+	return func(n *Node) {
+		n.name = name
+	}
 }
 
-// To cut or to enlarge input fields.
-func resizeSlice(s []interface{}, become int) []interface{} {
-	switch cur := len(s); {
-	case cur == become:
-	case cur < become:
-		// Enlarged fields are set to nil.
-		s = append(s, make([]interface{}, become-cur)...)
-	case cur > become:
-		s = s[0:become]
+// Takes over the rendering of a row. width is the width of the lines of the table, separators and
+// borders included. Returns the line to print without line break.
+type RowRenderer func(r *Row, width int) string
+
+// To render the row of the node by itself while the rest of the table stays columnar,
+// e.g. a separator row of dashes sized to the current table width:
+//   NewNode(WithRowRenderer(func(r *Row, width int) string {
+//     return strings.Repeat("-", width)
+//   }))
+func WithRowRenderer(fn RowRenderer) NodeOpt {
+	return func(n *Node) {
+		n.renderer = fn
 	}
-	return s
 }
 
-// A comparator looks like this:
-//   func(a, b interface{}) {
-//     return a.(int) < b.(int)
-//   }
-// It is passed to generate a sort.Less() function.
-type CmpFn func(a, b interface{}) bool
+// Stores alignment and width.
+type Column struct {
+	width int
+	pad   struct {
+		fixed  bool
+		right  bool
+		center bool
+	}
 
-// Less() in sort.Interface
-type lessFn func(i, j int) bool
+	// Wraps cells wider than this, 0 means no wrapping.
+	wrap int
 
-// An adapter to transform Node.nodes to be sort with sort.Stable().
-type sortable struct {
-	nodes
+	// Floor of an auto-width column.
+	min int
 
-	// Positions the x on this column, leaves y variant, i.e. to compare value on this field.
-	col int
+	// Converts fields into the string representation of the column instead of MustToString().
+	format func(fields []interface{}, col int) string
 
-	count int
+	// Share of the width distributed by Printing, 0 means the column keeps its width.
+	weight int
 
-	// Sort in descending order
-	desc bool
+	// Set by Printing if the width has been distributed, cells wider than it are cut.
+	cut bool
+
+	// Columns are hidden in ascending order of it if lines are too wide, 0 means never hidden.
+	priority int
+
+	// Set by WithHidden(), or by Printing if the column is dropped to fit the width.
+	hidden bool
+
+	// Replaces raw values before conversion if ok.
+	parse func(v interface{}) (parsed interface{}, ok bool)
+
+	// Widest line of the cells split at newlines, and widest cell with newlines escaped.
+	// Printing uses them instead of width by WithNewlines().
+	lineWidth, escWidth int
+
+	// Template of the URLs the cells link to, "{value}" is replaced. Empty means no links.
+	link string
+
+	// Widest URL of the cells, Printing prints URLs instead of cells in plain mode.
+	linkWidth int
+
+	// Title of the column for headers, lookups and exports, could be empty.
+	name string
+
+	// Derives the raw values of the column from the other fields of rows, could be nil.
+	compute func(r *Row) interface{}
+}
+
+// Turns current column into a format string, e.g.: "%3s", "%-5s".
+// fmt can't center, a centered column is turned into a right-aligned one. Printing pads it by fill().
+// fmt also counts ANSI escape sequences in, Printing measures the visible width instead.
+func (c Column) String() string {
+	if s := strconv.FormatInt(int64(c.width), 10); c.pad.right {
+		return "%-" + s + "s"
+	} else {
+		return "%" + s + "s"
+	}
+}
+
+// Pads s with spaces to the column width, the same way as fmt does with String() but also centers.
+// Centering puts the odd space to the right. s longer than the width is returned as is. s is
+// measured with m.
+func (c Column) fill(s string, m runeWidths) string {
+	n := c.width - m.strWidth(s)
+	if n <= 0 {
+		return s
+	}
+
+	switch {
+	case c.pad.center:
+		return strings.Repeat(" ", n/2) + s + strings.Repeat(" ", n-n/2)
+	case c.pad.right:
+		return s + strings.Repeat(" ", n)
+	default:
+		return strings.Repeat(" ", n) + s
+	}
+}
+
+// Returns a Column instance. Column options are:
+//
+// WithWidth(int): by default all columns are auto-width. Set to fix-width. WithWidth(20) is translated to "%20s".
+//
+// WithLeftAlignment(): set to pad to the right. For example: WithWidth(20), WithLeftAlignment() = "%-20s".
+//
+// WithCenterAlignment(): set to pad on both sides.
+//
+// WithWrap(int): wrap cells across multiple lines at a max width.
+//
+// WithMinWidth(int): set a floor width of an auto-width column.
+//
+// WithFormatter(func(interface{}) string): convert fields of the column by a function.
+//
+// WithRowFormatter(func([]interface{}, int) string): convert fields of the column with the whole row.
+//
+// WithVerb(string): convert fields of the column by a fmt verb, e.g. "%x", "%.2f".
+//
+// WithWeight(int): set the share of the width distributed by WithFitWidth().
+//
+// WithPriority(int): hide the column by its priority if lines are wider than WithFitWidth().
+//
+// WithDateParsing(*time.Location, ...string): store date-like strings of the column as time.Time.
+//
+// WithLinkTemplate(string): turn cells into hyperlinks.
+//
+// WithTimeFormat(string, *time.Location): render time.Time fields with a layout.
+//
+// WithPrecision(int): render float fields with fixed decimal places.
+//
+// WithHumanBytes(): render numeric fields as byte sizes, e.g. "1.0 MiB".
+//
+// WithPercent(int): render numeric fields as right-aligned percentages, e.g. "25.6%".
+//
+// WithCurrency(CurrencyFormat): render numeric fields as money values, e.g. "$1,234.50".
+//
+// WithBoolText(string, string): render bool fields as custom texts, e.g. "yes" and "no".
+//
+// WithColumnName(string): name the column for headers, lookups and exports.
+//
+// WithHidden(): never print the column, keep its data for sorting and exports.
+func NewColumn(opts ...ColumnOpt) Column {
+	c := Column{}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if !c.pad.fixed && c.width < c.min {
+		c.width = c.min
+	}
+	return c
+}
+
+type ColumnOpt func(*Column)
+
+// By default all columns are auto-width. Set to fix-width. WithWidth(20) is translated to "%20s".
+func WithWidth(w int) ColumnOpt {
+	return func(c *Column) {
+		if w < 0 {
+			w = 0
+		}
+		c.width = w
+		c.pad.fixed = true
+	}
+}
+
+// Set to pad to the right. For example: WithWidth(20), WithLeftAlignment() = "%-20s".
+func WithLeftAlignment() ColumnOpt {
+	return func(c *Column) {
+		c.pad.right = true
+		c.pad.center = false
+	}
+}
+
+// Wrap cells across multiple lines at spaces, words wider than the column are broken. An auto-width
+// column grows up to w, a fixed-width column wraps at its width. The other columns of the row are
+// padded on the continuation lines. w <= 0 means no wrapping.
+func WithWrap(w int) ColumnOpt {
+	return func(c *Column) {
+		if w < 0 {
+			w = 0
+		}
+		c.wrap = w
+	}
+}
+
+// Set a floor width of an auto-width column, so that sparse data doesn't produce columns too narrow
+// to be readable. The column still grows if the content is wider. Ignored by fix-width columns.
+func WithMinWidth(w int) ColumnOpt {
+	return func(c *Column) {
+		if w < 0 {
+			w = 0
+		}
+		c.min = w
+	}
+}
+
+// Convert fields of the column by fn instead of MustToString(). Raw values are kept for sorting.
+// It replaces the formatter set by WithRowFormatter() or WithVerb().
+func WithFormatter(fn func(v interface{}) string) ColumnOpt {
+	return func(c *Column) {
+		c.format = func(fields []interface{}, col int) string {
+			return fn(fields[col])
+		}
+	}
+}
+
+// Convert fields of the column by fn, which receives all the fields of the row and the column index,
+// so that a cell can render relative to its row, e.g. "↑" or "↓" by comparing two other columns.
+// The fields have been shrinked or enlarged to fit the schema.
+// It replaces the formatter set by WithFormatter() or WithVerb().
+func WithRowFormatter(fn func(fields []interface{}, col int) string) ColumnOpt {
+	return func(c *Column) {
+		c.format = fn
+	}
+}
+
+// Convert fields of the column by a fmt verb instead of MustToString(), e.g. "%x", "%.2f", "%+d".
+// Padding and alignment are layered on top, so the verb shouldn't contain a width:
+// WithWidth(8), WithVerb("%.2f") prints 3.14159 as "    3.14". nil fields are empty strings.
+// It replaces the formatter set by WithFormatter() or WithRowFormatter().
+func WithVerb(verb string) ColumnOpt {
+	return WithFormatter(func(v interface{}) string {
+		if v == nil {
+			return ""
+		}
+		return fmt.Sprintf(verb, v)
+	})
+}
+
+// Set the share of the width distributed to the column when printing with WithFitWidth().
+// Weights are relative to each other, percentages work if they sum up to 100.
+func WithWeight(w int) ColumnOpt {
+	return func(c *Column) {
+		if w < 0 {
+			w = 0
+		}
+		c.weight = w
+	}
+}
+
+// Let Printing hide the column if lines are wider than WithFitWidth() even when the weighted
+// columns are at their floors. Columns of the lowest priority are hidden first, and a note like
+// "(2 columns hidden)" is printed below the table. Columns without priorities are never hidden.
+func WithPriority(n int) ColumnOpt {
+	return func(c *Column) {
+		if n < 0 {
+			n = 0
+		}
+		c.priority = n
+	}
+}
+
+// Store string fields of the column that parse with one of the layouts as time.Time, so that
+// imported text data sorts as time. Layouts are tried in order, e.g. "02/01/2006" before
+// "2006-01-02". Dates without time zone information are in loc, nil means UTC.
+// Other fields are kept as is.
+func WithDateParsing(loc *time.Location, layouts ...string) ColumnOpt {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return func(c *Column) {
+		c.parse = func(v interface{}) (interface{}, bool) {
+			s, ok := v.(string)
+			if !ok {
+				return nil, false
+			}
+			for _, layout := range layouts {
+				if t, err := time.ParseInLocation(layout, strings.TrimSpace(s), loc); err == nil {
+					return t, true
+				}
+			}
+			return nil, false
+		}
+	}
+}
+
+// Turn cells into hyperlinks to tmpl with "{value}" replaced by the path-escaped cell, e.g.
+// "https://ci.example.com/build/{value}". Terminals get OSC 8 hyperlinks, HTML gets <a> elements and
+// plain mode prints the URLs instead of the cells. Empty cells aren't linked.
+func WithLinkTemplate(tmpl string) ColumnOpt {
+	return func(c *Column) {
+		c.link = tmpl
+	}
+}
+
+// Returns the URL s links to, or an empty string if it doesn't link.
+func (c Column) href(s string) string {
+	if s = stripANSI(s); c.link == "" || s == "" {
+		return ""
+	}
+	return strings.ReplaceAll(c.link, "{value}", url.PathEscape(s))
+}
+
+// Name the column, e.g. "AGE". Names are the titles of WithHeader() without titles, look up columns
+// by ColumnSchema.Index(), e.g. for Node.SortByName(), and name fields of exports.
+func WithColumnName(name string) ColumnOpt {
+	return func(c *Column) {
+		c.name = name
+	}
+}
+
+// Never print the column, e.g. a raw timestamp to sort by next to a humanized one. Its fields are
+// still stored, measured, sorted by and exported by Sink and JSON responses. Header and footer titles
+// are still matched to the columns of the schema.
+func WithHidden() ColumnOpt {
+	return func(c *Column) {
+		c.hidden = true
+	}
+}
+
+// Set to pad on both sides. For example: WithWidth(5), WithCenterAlignment() pads "a" to "  a  ".
+func WithCenterAlignment() ColumnOpt {
+	return func(c *Column) {
+		c.pad.center = true
+		c.pad.right = false
+	}
+}
+
+// Defines how many columns in a row and their corresponding Column data.
+type ColumnSchema struct {
+	cols  []Column
+	count int
+
+	// Keeps the columns and their widths as they are, set by Freeze().
+	frozen bool
+
+	// How rows change the widths, see SetWidthPolicy().
+	policy WidthPolicy
+
+	// Defers measuring rows until the PushAll() call pushing them ends.
+	batching bool
+}
+
+// How the auto widths of a schema follow its rows.
+type WidthPolicy int
+
+const (
+	// Widths grow as rows are created and never shrink, the default. The fastest, but a removed
+	// or replaced row may leave its columns wider than the rest needs.
+	WidthGrow WidthPolicy = iota
+
+	// Widths grow as rows are created, and are measured again from the rows to print by each
+	// RunNode() call, so they fit the printed rows tightly, e.g. after Limit() or WithMaxDepth().
+	WidthRecalc
+
+	// Widths are a snapshot taken when the policy is set, rows no longer change them. Cells wider
+	// than their columns overflow them, unless the columns wrap.
+	WidthFixed
+)
+
+// Sets how the auto widths of receiver follow its rows. Defaults to WidthGrow. A frozen schema
+// keeps its widths regardless.
+func (s *ColumnSchema) SetWidthPolicy(p WidthPolicy) {
+	s.policy = p
+}
+
+// Returns true if rows no longer change the widths of receiver.
+func (s *ColumnSchema) fixed() bool {
+	return s.frozen || s.policy == WidthFixed
+}
+
+// Freezes receiver, so that rows no longer widen its columns, e.g. to reuse one canonical layout
+// without later rows silently widening it. Cells wider than their columns overflow them, unless the
+// columns wrap. Columns can't be added or dropped, nor widths merged or recalculated, extra values
+// of dynamic rows are dropped. It can't be undone.
+func (s *ColumnSchema) Freeze() {
+	s.frozen = true
+}
+
+// Returns true if receiver has been frozen by Freeze().
+func (s *ColumnSchema) Frozen() bool {
+	return s.frozen
+}
+
+// Returns the names of the columns given by WithColumnName(), empty strings for unnamed ones.
+func (s *ColumnSchema) Names() []string {
+	names := make([]string, s.count)
+	for i, c := range s.cols {
+		names[i] = c.name
+	}
+	return names
+}
+
+// Returns the index of the first column named name, or -1.
+func (s *ColumnSchema) Index(name string) int {
+	for i, c := range s.cols {
+		if c.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Returns true if every column has a name.
+func (s *ColumnSchema) named() bool {
+	for _, c := range s.cols {
+		if c.name == "" {
+			return false
+		}
+	}
+	return s.count > 0
+}
+
+// Widens the auto-width columns of receiver and other to the wider of each pair, so that two trees
+// built independently print with one consistent layout, without sharing a schema as PushNode()
+// requires. Columns missing a name take the name of their pair. Columns beyond the count of either
+// schema are left alone, as a schema can't resize the rows of its own, see Node.MergeSchema() for
+// the union of the columns. A frozen schema, or one of WidthFixed, keeps its widths.
+func (s *ColumnSchema) Merge(other *ColumnSchema) {
+	for i := 0; i < s.count && i < other.count; i++ {
+		a, b := &s.cols[i], &other.cols[i]
+		if !s.fixed() {
+			a.widen(*b)
+		}
+		if !other.fixed() {
+			b.widen(*a)
+		}
+		if a.name == "" {
+			a.name = b.name
+		}
+		if b.name == "" {
+			b.name = a.name
+		}
+	}
+}
+
+// Unions the columns of the schemas of receiver and other, then merges them by
+// ColumnSchema.Merge(). The shorter schema gets the columns it misses from the longer one, and the
+// rows of its tree get nils for them. Returns any error encountered, both trees are untouched then.
+//
+// The tree of a shorter schema gets a copy of it having the columns appended, as AppendColumn()
+// does, other trees sharing the schema keep it as it was.
+func (n *Node) MergeSchema(other *Node) error {
+	if other == nil || n.schema == nil || other.schema == nil {
+		return fmt.Errorf("MergeSchema: no schema to merge")
+	}
+	a, b := n, other
+	if a.schema.count > b.schema.count {
+		a, b = b, a
+	}
+	if s := a.schema; s.count < b.schema.count {
+		if s.frozen {
+			return fmt.Errorf("MergeSchema: schema is frozen")
+		}
+		union := s.Clone()
+		union.cols = append(union.cols, b.schema.cols[s.count:]...)
+		union.count = len(union.cols)
+		a.rebase(s, union, func(r *Row) {
+			r.fields = append([]interface{}(nil), r.fields...)
+			r.prepare()
+		})
+	}
+	n.schema.Merge(other.schema)
+	return nil
+}
+
+// Grows the widths of an auto-width column to the ones of o.
+func (c *Column) widen(o Column) {
+	if c.pad.fixed {
+		return
+	}
+	c.width = max(c.width, o.width)
+	c.lineWidth = max(c.lineWidth, o.lineWidth)
+	c.escWidth = max(c.escWidth, o.escWidth)
+	c.linkWidth = max(c.linkWidth, o.linkWidth)
+}
+
+// Returns a copy of receiver with the same columns, widths, width policy and frozen state, e.g. to
+// lay out a tree like another one without the rows of either widening the columns of the other.
+// Schemas are shared by pointer otherwise. A nil receiver returns nil.
+func (s *ColumnSchema) Clone() *ColumnSchema {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	c.cols = append([]Column(nil), s.cols...)
+	c.batching = false
+	return &c
+}
+
+// Shrinks auto widths back to their minimum, for rows to measure them again.
+func (s *ColumnSchema) resetWidths() {
+	if s.fixed() {
+		return
+	}
+	resetWidths(s.cols)
+}
+
+// Resets the auto widths of cols to their minimums.
+func resetWidths(cols []Column) {
+	for i := range cols {
+		if c := &cols[i]; !c.pad.fixed {
+			c.width, c.lineWidth, c.escWidth, c.linkWidth = c.min, 0, 0, 0
+		}
+	}
+}
+
+func NewSchema(c ...Column) *ColumnSchema {
+	return &ColumnSchema{
+		cols:  c,
+		count: len(c),
+	}
+}
+
+// Creates a column schema instance with N columns. N is the length of input fields.
+func NewSchemaFrom(fields []interface{}) *ColumnSchema {
+	size := len(fields)
+
+	return &ColumnSchema{
+		cols:  make([]Column, size),
+		count: size,
+	}
+}
+
+// Stores both raw input values and their string representations.
+type Row struct {
+
+	// Defines how many columns this row contains.
+	schema *ColumnSchema
+
+	// Raw input values, has been shrinked or enlarged by current column schema.
+	fields []interface{}
+
+	// String representations of Row.fields. Used to calculate padding and fmt.Printf().
+	fmtArgs []interface{}
+
+	// Converts fields into fmtArgs, could be nil.
+	ingest *ingestion
+}
+
+// Traverses format strings with String() on each Column instance.
+func (r *Row) EachFmtStr(fn func(string)) {
+	for _, c := range r.schema.cols {
+		fn(c.String())
+	}
+}
+
+// Returns the string slice that stores the string representations of raw values.
+func (r *Row) FmtArgs() []interface{} {
+	return r.fmtArgs
+}
+
+// Returns a string by calling fmt.Fprintf() on fmtStr and fmtArgs.
+func (r *Row) String() string {
+	var b strings.Builder
+	NewPrinting(WithWriter(&b), WithColSep(" "), WithLineBrk("")).RunRow(r)
+	return b.String()
+}
+
+func (r *Row) Schema() *ColumnSchema {
+	return r.schema
+}
+
+// Initializes a Row instance, on each creation:
+//
+// 1. if no schema found, create a new one based on current data.
+// 2. if with schema, shrink or enlarge input fields to fit to the schema.
+// 3. parse fields by the columns that ask to.
+// 4. compute fields of the computed columns.
+// 5. do string conversion, calculate string length, updates to schema instance.
+func (r *Row) prepare() {
+	switch fs := r.fields; r.schema == nil {
+	case true:
+		// auto creation
+		r.schema = NewSchemaFrom(fs)
+	case false:
+		// shrink or enlarge fields
+		r.fields = resizeSlice(fs, r.schema.count)
+	}
+
+	// Parsing replaces raw values, copies them first to leave the input slice alone.
+	copied := false
+	for i, c := range r.schema.cols {
+		if c.parse == nil {
+			continue
+		}
+		if v, ok := c.parse(r.fields[i]); ok {
+			if !copied {
+				r.fields = append([]interface{}(nil), r.fields...)
+				copied = true
+			}
+			r.fields[i] = v
+		}
+	}
+	for i, c := range r.schema.cols {
+		if c.compute == nil {
+			continue
+		}
+		if !copied {
+			r.fields = append([]interface{}(nil), r.fields...)
+			copied = true
+		}
+		r.fields[i] = c.compute(r)
+	}
+
+	r.fmtArgs = make([]interface{}, r.schema.count)
+
+	for i := 0; i < r.schema.count; i++ {
+		var s string
+		if f := r.schema.cols[i].format; f != nil {
+			s = f(r.fields, i)
+		} else {
+			s = r.ingest.toString(r.fields[i])
+		}
+		r.fmtArgs[i] = r.ingest.clean(s)
+	}
+	r.measure()
+}
+
+// Grows the widths of the schema to fit the string representations.
+func (r *Row) measure() {
+	if r.schema.fixed() || r.schema.batching {
+		return
+	}
+	r.measureInto(r.schema.cols, nil)
+}
+
+// Grows the widths of cols, the columns of the schema or a copy, to fit the string representations
+// measured with m.
+func (r *Row) measureInto(cols []Column, m runeWidths) {
+	for i, a := range r.fmtArgs {
+		if c := &cols[i]; !c.pad.fixed {
+			// only updates to those without fixed width
+			s := a.(string)
+			c.width = c.grown(c.width, m.strWidth(s))
+			c.lineWidth = c.grown(c.lineWidth, m.maxLineWidth(s))
+			c.escWidth = c.grown(c.escWidth, m.strWidth(escapeNewlines(s)))
+			if c.link != "" {
+				c.linkWidth = c.grown(c.linkWidth, m.strWidth(c.href(s)))
+			}
+		}
+	}
+}
+
+// Returns the larger of width and w, w is capped by wrap.
+func (c *Column) grown(width, w int) int {
+	if c.wrap > 0 && w > c.wrap {
+		w = c.wrap
+	}
+	if w > width {
+		return w
+	}
+	return width
+}
+
+// Returns a pointer to a Row instance. Row options are:
+//
+// WithRowSchema(*ColumnSchema): to inherit the schema from an existing row or node.
+//
+// WithRowSchemaCopy(*ColumnSchema): to start from a copy of the schema of an existing row or node, isolated from it.
+//
+// WithRowColumns(...Column): to create a row with provided column schema.
+//
+// WithData(...interface{}): set data to the row.
+func NewRow(opts ...RowOpt) *Row {
+	r := &Row{}
+	for _, opt := range opts {
+		opt(r)
+	}
+	r.prepare()
+	return r
+}
+
+type RowOpt func(*Row)
+
+// To inherit the schema from an existing row or node.
+func WithRowSchema(s *ColumnSchema) RowOpt {
+	return func(r *Row) {
+		r.schema = s
+	}
+}
+
+// To start from a copy of the schema of an existing row or node, so that the row doesn't widen
+// the columns of the original. The row can't be pushed to nodes of the original then. See
+// ColumnSchema.Clone().
+func WithRowSchemaCopy(s *ColumnSchema) RowOpt {
+	return func(r *Row) {
+		r.schema = s.Clone()
+	}
+}
+
+// To create a row with provided column schema.
+func WithRowColumns(c ...Column) RowOpt {
+	return func(r *Row) {
+		r.schema = NewSchema(c...)
+	}
+}
+
+// Set data to the row.
+func WithRowData(a ...interface{}) RowOpt {
+	return func(r *Row) {
+		r.fields = a
+	}
+}
+
+// Conversions registered by RegisterStringer(), holds a []func(interface{}) (string, bool).
+var (
+	stringers   atomic.Value
+	stringersMu sync.Mutex
+)
+
+// Customize how MustToString() converts values across all rows, e.g. of the types of an application,
+// instead of converting every value before Push(). fn returns false to leave a value to the next
+// conversion. Conversions are tried in the order of registration, before the builtin ones.
+//
+// Conversions apply to the whole process, register them before pushing rows.
+func RegisterStringer(fn func(interface{}) (string, bool)) {
+	stringersMu.Lock()
+	defer stringersMu.Unlock()
+
+	fns, _ := stringers.Load().([]func(interface{}) (string, bool))
+	stringers.Store(append(append([]func(interface{}) (string, bool)(nil), fns...), fn))
+}
+
+// Converts anything to a string. The function itself handles the common types including:
+// error, fmt.Stringer, string, []byte, uint, int, maps and nil. It passes anything else to the fmt.Sprintf
+// to get the string representation of that value. It is used when initializing a Row instance.
+//
+// Maps are turned into sorted "k=v" pairs by FormatMap().
+//
+// Conversions registered by RegisterStringer() are tried first.
+func MustToString(a interface{}) string {
+	if fns, _ := stringers.Load().([]func(interface{}) (string, bool)); fns != nil {
+		for _, fn := range fns {
+			if s, ok := fn(a); ok {
+				return s
+			}
+		}
+	}
+
+	var s string
+
+	switch v := a.(type) {
+	case error:
+		s = v.Error()
+	case fmt.Stringer:
+		s = v.String()
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case uint:
+		s = strconv.FormatUint(uint64(v), 10)
+	case int:
+		s = strconv.FormatInt(int64(v), 10)
+	case nil:
+	default:
+		if reflect.TypeOf(v).Kind() == reflect.Map {
+			s = FormatMap(v)
+		} else {
+			s = fmt.Sprintf("%v", v)
+		}
+	}
+
+	return s
+}
+
+// Turns a map into space separated "k=v" pairs sorted by keys, e.g. "a=1 b=2", so that a map field
+// always renders the same. Keys are compared by MatchCmp() if possible, or by their string
+// representations. Both keys and values are converted by MustToString(). Returns an empty string
+// if m isn't a map.
+func FormatMap(m interface{}) string {
+	rv := reflect.ValueOf(m)
+	if rv.Kind() != reflect.Map || rv.Len() == 0 {
+		return ""
+	}
+
+	keys := rv.MapKeys()
+	less := func(i, j int) bool {
+		return MustToString(keys[i].Interface()) < MustToString(keys[j].Interface())
+	}
+	if rv.Type().Key().Kind() != reflect.Interface {
+		if cmp := MatchCmp(keys[0].Interface()); cmp != nil {
+			less = func(i, j int) bool { return cmp(keys[i].Interface(), keys[j].Interface()) }
+		}
+	}
+	sort.Slice(keys, less)
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = MustToString(k.Interface()) + "=" + MustToString(rv.MapIndex(k).Interface())
+	}
+	return strings.Join(pairs, " ")
+}
+
+// To cut or to enlarge input fields.
+func resizeSlice(s []interface{}, become int) []interface{} {
+	switch cur := len(s); {
+	case cur == become:
+	case cur < become:
+		// Enlarged fields are set to nil.
+		s = append(s, make([]interface{}, become-cur)...)
+	case cur > become:
+		s = s[0:become]
+	}
+	return s
+}
+
+// A comparator looks like this:
+//   func(a, b interface{}) {
+//     return a.(int) < b.(int)
+//   }
+// It is passed to generate a sort.Less() function.
+type CmpFn func(a, b interface{}) bool
+
+// Less() in sort.Interface
+type lessFn func(i, j int) bool
+
+// An adapter to transform Node.nodes to be sort with sort.Stable().
+type sortable struct {
+	nodes
+
+	// Positions the x on this column, leaves y variant, i.e. to compare value on this field.
+	col int
+
+	count int
+
+	// Sort in descending order
+	desc bool
+
+	// Sort the children of descendants as well
+	recursive bool
+
+	// Derives the values to compare from nodes instead of the column, could be nil.
+	key func(n *Node) interface{}
+
+	// Derived values by key, swapped along with nodes.
+	keys []interface{}
+
+	// Where nil values go: 0 doesn't allow them, -1 places them first and 1 last.
+	nils int
+
+	// Compare string representations of values that can't be compared otherwise.
+	fallback bool
+
+	// Keeps only the first limit children after sorting if limited.
+	limit   int
+	limited bool
+
+	less lessFn
+
+	// A chain of func that generates a CmpFn.
+	chain []func(a interface{}) CmpFn
+}
+
+// Find a CmpFn that is able to handle (do comparison on) type of a.
+func (s *sortable) matchComparator(a interface{}) (cmp CmpFn, ok bool) {
+	for _, matcher := range s.chain {
+		cmp := matcher(a)
+		if cmp != nil {
+			return cmp, true
+		}
+	}
+	return nil, false
+}
+
+func (s *sortable) holdsIdenticalType() bool {
+	first, ok := s.sample()
+	if !ok {
+		return true
+	}
+	for i := 0; i < s.count; i++ {
+		a := s.cell(i)
+		if a == nil && s.nils != 0 {
+			continue
+		}
+		if reflect.TypeOf(a) != reflect.TypeOf(first) {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the first value to compare, skipping nil values if they are placed at one end.
+func (s *sortable) sample() (interface{}, bool) {
+	for i := 0; i < s.count; i++ {
+		if a := s.cell(i); a != nil || s.nils == 0 {
+			return a, true
+		}
+	}
+	return nil, false
+}
+
+// Wraps less to place nil values at one end, whatever the order of the others.
+func (s *sortable) placeNils(less lessFn) lessFn {
+	if s.nils == 0 {
+		return less
+	}
+	return func(i, j int) bool {
+		a, b := s.cell(i) == nil, s.cell(j) == nil
+		switch {
+		case a && b:
+			return false
+		case a:
+			return s.nils < 0
+		case b:
+			return s.nils > 0
+		}
+		return less(i, j)
+	}
+}
+
+func (s *sortable) toLess(cmp CmpFn) lessFn {
+	if s.desc {
+		return func(i, j int) bool { return !cmp(s.cell(i), s.cell(j)) }
+	} else {
+		return func(i, j int) bool { return cmp(s.cell(i), s.cell(j)) }
+	}
+}
+
+// Cell retrieving method on nth column, or the derived key of the row
+func (s *sortable) cell(row int) interface{} {
+	if s.keys != nil {
+		return s.keys[row]
+	}
+	return s.nodes.cell(s.col, row)
+}
+
+func (s *sortable) Len() int {
+	return s.count
+}
+
+func (s *sortable) Swap(i, j int) {
+	s.nodes[j], s.nodes[i] = s.nodes[i], s.nodes[j]
+	if s.keys != nil {
+		s.keys[j], s.keys[i] = s.keys[i], s.keys[j]
+	}
+}
+
+func (s *sortable) Less(i, j int) bool {
+	return s.less(i, j)
+}
+
+// []*Node is a 2-dimensions slice. (table) And to sort the table's rows, we need:
+//
+// 1. compare in what type of the field value in which column.
+// 2. all values in that column must be in identical type.
+// 3. if we can sort the type of that field value.
+func createSortableOn(column int, ns []*Node, opts ...SortOpt) (*sortable, error) {
+	s := &sortable{
+		nodes: nodes(ns),
+		col:   column,
+		count: len(ns),
+
+		// Set up a fallback Less(), so that an incidental call to Less() won't panic on an unit Sortable instance.
+		less: func(i, j int) bool { return true },
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	// Put the default CmpFn finder.
+	s.chain = append(s.chain, MatchCmp)
+
+	if s.key != nil {
+		s.keys = make([]interface{}, s.count)
+		for i, n := range s.nodes {
+			s.keys[i] = s.key(n)
+		}
+	}
+
+	if first, ok := s.sample(); ok {
+		var cmp CmpFn
+		switch identical := s.holdsIdenticalType(); {
+		case !identical && !s.fallback:
+			return nil, fmt.Errorf("createSortableOn: column %d doesn't contain identical value type", column)
+		case identical:
+			cmp, _ = s.matchComparator(first)
+		}
+		if cmp == nil {
+			if !s.fallback {
+				return nil, fmt.Errorf("createSortableOn: don't know how to sort %s", reflect.TypeOf(first))
+			}
+			cmp = func(a, b interface{}) bool { return MustToString(a) < MustToString(b) }
+		}
+		s.less = s.placeNils(s.toLess(cmp))
+	} else if s.nils != 0 {
+		// Nothing but nil values.
+		s.less = func(i, j int) bool { return false }
+	}
+
+	return s, nil
+}
+
+type SortOpt func(*sortable)
+
+func WithDescending() SortOpt {
+	return func(s *sortable) {
+		s.desc = true
+	}
+}
+
+// Sort the children of every descendant in the same way, in one call. Sorting stops at the first
+// error, e.g. a subtree whose schema doesn't have the column.
+func WithRecursive() SortOpt {
+	return func(s *sortable) {
+		s.recursive = true
+	}
+}
+
+// Sort on a key derived from each row instead of the raw value of the column, e.g. the lowercase
+// of a column or a combination of fields. Keys are derived once per sort, and compared the same way
+// as raw values: they must be of an identical type that a matcher can compare.
+func WithSortKey(fn func(r *Row) interface{}) SortOpt {
+	return func(s *sortable) {
+		s.key = func(n *Node) interface{} { return fn(n.Row()) }
+	}
+}
+
+// Sort on a key derived from each child node, like WithSortKey(), e.g. on metadata set by Node.Set().
+func WithNodeSortKey(fn func(n *Node) interface{}) SortOpt {
+	return func(s *sortable) {
+		s.key = fn
+	}
+}
+
+// Place nil values first, and sort the others as usual. It doesn't change with WithDescending().
+func WithNilsFirst() SortOpt {
+	return func(s *sortable) {
+		s.nils = -1
+	}
+}
+
+// Place nil values last, and sort the others as usual. It doesn't change with WithDescending().
+func WithNilsLast() SortOpt {
+	return func(s *sortable) {
+		s.nils = 1
+	}
+}
+
+// Sort values of mixed or unknown types on their string representations given by MustToString(),
+// instead of returning an error. Values of an identical type with a comparator are compared as
+// usual.
+func WithStringFallback() SortOpt {
+	return func(s *sortable) {
+		s.fallback = true
+	}
+}
+
+// Keep only the first n children after sorting, and detach the rest, see Node.Limit(). Along with
+// WithRecursive(), the children of every descendant are limited as well.
+func WithLimit(n int) SortOpt {
+	return func(s *sortable) {
+		s.limit, s.limited = n, true
+	}
+}
+
+// Sort strings ignoring case, e.g. "apple" before "Banana". Strings differing only in case keep
+// their order.
+func WithCaseInsensitive() SortOpt {
+	return WithCmpMatchers(func(a interface{}) CmpFn {
+		if _, ok := a.(string); !ok {
+			return nil
+		}
+		return func(a, b interface{}) bool {
+			return strings.ToLower(a.(string)) < strings.ToLower(b.(string))
+		}
+	})
+}
+
+// Sort strings by compare, which returns a negative number, 0 or a positive number if a sorts
+// before, with or after b, so that non-ASCII strings sort correctly for a locale. It's usually the
+// CompareString method of a collator from golang.org/x/text/collate:
+//   c := collate.New(language.German)
+//   n.Sort(0, WithCollation(c.CompareString))
+func WithCollation(compare func(a, b string) int) SortOpt {
+	return WithCmpMatchers(func(a interface{}) CmpFn {
+		if _, ok := a.(string); !ok {
+			return nil
+		}
+		return func(a, b interface{}) bool {
+			return compare(a.(string), b.(string)) < 0
+		}
+	})
+}
+
+// Multiple matcher functions can be provided as input.
+// The method executes them in order until a matcher can handle the current comparing type.
+// A finder should look like this:
+//   func(a interface{}) {
+//     // you can do type switch on a to find a exact type of the input value,
+//     // or simply ignores it if you know in advance the field type you are comparing to.
+//     return func(a, b interface{}) { return a.(int) < b.(int) }
+//   }
+// See MatchCmp() to learn how to write a matcher.
+func WithCmpMatchers(m ...func(interface{}) CmpFn) SortOpt {
+	return func(s *sortable) {
+		s.chain = append(s.chain, m...)
+	}
+}
+
+// The default CmpFn matcher used in createSortableOn(). It uses type switch to find the type it can compare.
+// It supports types of string, bool (false first), signed and unsigned integers, floats, time.Duration
+// and time.Time.
+func MatchCmp(a interface{}) CmpFn {
+	var out CmpFn
+	switch a.(type) {
+	case string:
+		out = func(a, b interface{}) bool { return a.(string) < b.(string) }
+	case bool:
+		out = func(a, b interface{}) bool { return !a.(bool) && b.(bool) }
+	case int:
+		out = func(a, b interface{}) bool { return a.(int) < b.(int) }
+	case int8:
+		out = func(a, b interface{}) bool { return a.(int8) < b.(int8) }
+	case int16:
+		out = func(a, b interface{}) bool { return a.(int16) < b.(int16) }
+	case int32:
+		out = func(a, b interface{}) bool { return a.(int32) < b.(int32) }
+	case int64:
+		out = func(a, b interface{}) bool { return a.(int64) < b.(int64) }
+	case uint:
+		out = func(a, b interface{}) bool { return a.(uint) < b.(uint) }
+	case uint8:
+		out = func(a, b interface{}) bool { return a.(uint8) < b.(uint8) }
+	case uint16:
+		out = func(a, b interface{}) bool { return a.(uint16) < b.(uint16) }
+	case uint32:
+		out = func(a, b interface{}) bool { return a.(uint32) < b.(uint32) }
+	case uint64:
+		out = func(a, b interface{}) bool { return a.(uint64) < b.(uint64) }
+	case float32:
+		out = func(a, b interface{}) bool { return a.(float32) < b.(float32) }
+	case float64:
+		out = func(a, b interface{}) bool { return a.(float64) < b.(float64) }
+	case time.Duration:
+		out = func(a, b interface{}) bool { return a.(time.Duration) < b.(time.Duration) }
+	case time.Time:
+		out = func(a, b interface{}) bool { return a.(time.Time).Before(b.(time.Time)) }
+	}
+	return out
+}
+
+// Algorithm for printing.
+type Printing struct {
+	writer  io.Writer
+	colSep  string
+	lineBrk string
+
+	// Picks a writer per node, falls back to writer if it's nil or returns nil.
+	nodeWriter func(*Node) io.Writer
+
+	// Strips styling and decorations, overrides colSep.
+	plain bool
+
+	// Draws table borders if not nil, overrides colSep.
+	border *BorderStyle
+
+	// Titles of the header line, printed above the first row of each writer.
+	header []interface{}
+
+	// Cells of the footer line, printed below the last row of each writer.
+	footer []interface{}
+
+	// Indexes or names of the columns to print, nil means all.
+	columns []interface{}
+
+	// Caption printed above the table of each writer, centered over it if titleCentered.
+	title         string
+	titleCentered bool
+
+	// Width of the lines that weighted columns are fitted to, 0 means no fitting.
+	fitWidth int
+
+	// Widths of runes overriding the builtin ones, columns are measured again with them if not nil.
+	runeWidths runeWidths
+
+	// Styles cells by their raw values, could be nil.
+	cellStyler CellStyler
+
+	// Style of every other row, the zero Style means no striping.
+	zebra Style
+
+	// Styles rows by their nodes, could be nil.
+	rowStyler RowStyler
+
+	// Style of cells holding errors, the zero Style means no style.
+	errorStyle Style
+
+	// How cells containing newlines are printed.
+	newlines NewlineMode
+
+	// Prepends a column of row numbers.
+	rowNumbers bool
+
+	// Separates the subtrees of the children of the printed node.
+	groupSep bool
+
+	// Depth of the deepest rows to print, 0 means no limit. Marks hidden descendants if depthMarker.
+	maxDepth    int
+	depthMarker bool
+
+	// Prints only the rows of the nodes without children.
+	leavesOnly bool
+
+	// Skips the row of the node passed to RunNode().
+	skipReceiver bool
+
+	// Counters of the last run, passed to statsHook if it's not nil.
+	stats     Stats
+	statsHook func(Stats)
+}
+
+// Counters of a single RunNode() or RunRow() call.
+type Stats struct {
+	// Rows printed, the header excluded.
+	Rows int
+
+	// Physical lines written, including the header, rules and continuation lines.
+	Lines int
+
+	// Bytes written to all the writers.
+	Bytes int
+
+	// Cells that have been cut to fit.
+	Truncations int
+
+	// Cells that have been wrapped across multiple lines.
+	Wraps int
+}
+
+// Returns the counters of the last RunNode() or RunRow() call.
+func (p *Printing) Stats() Stats {
+	return p.stats
+}
+
+// Do nothing if n is nil.
+func (p *Printing) RunNode(n *Node) {
+	if n == nil {
+		return
+	}
+
+	ps := p.newPass()
+	steps := p.steps(n)
+	recalcWidths(steps)
+	var printed []*Row
+	for _, st := range steps {
+		if r := st.node.Row(); st.hidden == 0 && r != nil {
+			printed = append(printed, r)
+		}
+	}
+	ps.remeasure(printed)
+	if p.rowNumbers {
+		rows := 0
+		for _, st := range steps {
+			if r := st.node.Row(); st.hidden == 0 && r != nil && r.schema.count > 0 {
+				rows++
+			}
+		}
+		ps.numWidth = len(strconv.Itoa(rows))
+	}
+
+	for _, st := range steps {
+		w := p.writerOf(st.node)
+		t := ps.tableOf(w)
+		if p.groupSep && t.last != nil && t.group >= 0 && st.group != t.group {
+			ps.separate(t, ps.layout(t, t.last))
+		}
+		t.group = st.group
+
+		if st.hidden > 0 {
+			ps.marker(t, st.hidden)
+		} else {
+			ps.row(w, st.node, st.node.Row())
+		}
+	}
+	ps.close()
+}
+
+// A row or a marker RunNode() prints.
+type step struct {
+	node *Node
+
+	// Index of the child of the printed node the step belongs to, -1 for the printed node.
+	group int
+
+	// Descendants of node hidden by maxDepth, prints a marker instead of the row if it's not 0.
+	hidden int
+}
+
+// Returns the steps to print n, in the order of Walk().
+func (p *Printing) steps(n *Node) []step {
+	var (
+		steps []step
+		visit func(c *Node, group, depth int)
+	)
+	visit = func(c *Node, group, depth int) {
+		var (
+			cut  = p.maxDepth > 0 && depth == p.maxDepth
+			leaf = cut || len(c.nodes) == 0
+		)
+		// only root has no *Row
+		receiver := depth == 0 && (!c.IsNotRoot() || p.skipReceiver)
+		if !receiver && (leaf || !p.leavesOnly) {
+			steps = append(steps, step{node: c, group: group})
+		}
+		if cut {
+			if hidden := c.Size(); p.depthMarker && hidden > 0 {
+				steps = append(steps, step{node: c, group: group, hidden: hidden})
+			}
+			return
+		}
+		for i, cc := range c.nodes {
+			if depth == 0 {
+				group = i
+			}
+			visit(cc, group, depth+1)
+		}
+	}
+	visit(n, -1, 0)
+	return steps
+}
+
+// Measures the schemas of WidthRecalc again from the rows of steps to print.
+func recalcWidths(steps []step) {
+	var rows []*Row
+	seen := map[*ColumnSchema]bool{}
+	for _, st := range steps {
+		if r := st.node.Row(); st.hidden == 0 && r != nil && r.schema.policy == WidthRecalc {
+			if !seen[r.schema] {
+				seen[r.schema] = true
+				r.schema.resetWidths()
+			}
+			rows = append(rows, r)
+		}
+	}
+	for _, r := range rows {
+		r.measure()
+	}
+}
+
+// Do nothing if r is nil or there is no columns to print.
+func (p *Printing) RunRow(r *Row) {
+	ps := p.newPass()
+	ps.numWidth = 1
+	if r != nil {
+		ps.remeasure([]*Row{r})
+	}
+	ps.row(p.writer, nil, r)
+	ps.close()
+}
+
+// Returns the writer that the row of n should go to.
+func (p *Printing) writerOf(n *Node) io.Writer {
+	if p.nodeWriter != nil {
+		if w := p.nodeWriter(n); w != nil {
+			return w
+		}
+	}
+	return p.writer
+}
+
+func (p *Printing) newPass() *pass {
+	return &pass{Printing: p}
+}
+
+// Measures the columns of the schemas of rows again with runeWidths into copies, which layout()
+// starts from instead of the schemas. Frozen schemas and the ones of WidthFixed keep their widths.
+// Does nothing without runeWidths.
+func (ps *pass) remeasure(rows []*Row) {
+	if ps.runeWidths == nil {
+		return
+	}
+	ps.measured = map[*ColumnSchema][]Column{}
+	for _, r := range rows {
+		s := r.schema
+		if s == nil || s.fixed() {
+			continue
+		}
+		cols, ok := ps.measured[s]
+		if !ok {
+			cols = append([]Column(nil), s.cols...)
+			resetWidths(cols)
+			ps.measured[s] = cols
+		}
+		r.measureInto(cols, ps.runeWidths)
+	}
+}
+
+// State of a single RunNode() or RunRow() call. Each writer gets its own table.
+type pass struct {
+	*Printing
+
+	// In order of the first use.
+	tables []*table
+
+	stats Stats
+
+	// Width of the row number column, wide enough for the number of rows to print.
+	numWidth int
+
+	// Columns of the schemas measured with runeWidths, nil without them.
+	measured map[*ColumnSchema][]Column
+}
+
+type table struct {
+	w io.Writer
+
+	// Schema of the first printed row, which the header line is aligned to.
+	head *ColumnSchema
+
+	// Schema of the last printed row.
+	last *ColumnSchema
+
+	// Most columns hidden by a schema of the table.
+	hidden int
+
+	// Group of the last step printed to the table.
+	group int
+}
+
+func (ps *pass) tableOf(w io.Writer) *table {
+	for _, t := range ps.tables {
+		if t.w == w {
+			return t
+		}
+	}
+	t := &table{w: w}
+	ps.tables = append(ps.tables, t)
+	return t
+}
+
+// Prints r, and the top rule and the header if it's the first row of the table.
+// n is the node holding r, could be nil.
+func (ps *pass) row(w io.Writer, n *Node, r *Row) {
+	if r == nil || r.schema.count == 0 {
+		// Means no columns to print
+		return
+	}
+
+	t := ps.tableOf(w)
+	switch {
+	case t.last == nil:
+		t.head = r.schema
+		cols := ps.layout(t, r.schema)
+		if ps.title != "" {
+			ps.write(t, ps.titleLine(cols))
+		}
+		ps.rule(t, ruleTop, cols)
+		if ps.header != nil {
+			ps.line(t, cols, ps.titleArgs(ps.headerOf(r.schema), r.schema, "#"), nil, "")
+			ps.rule(t, ruleMid, cols)
+		}
+	case t.last != r.schema:
+		// Columns no longer line up, separates them.
+		ps.rule(t, ruleMid, ps.layout(t, r.schema))
+	}
+	t.last = r.schema
+
+	cols := ps.layout(t, r.schema)
+	var style Style
+	if n != nil && ps.rowStyler != nil {
+		style = ps.rowStyler(n, ps.stats.Rows)
+	}
+	if n != nil && n.renderer != nil {
+		ps.write(t, n.renderer(r, ps.lineWidth(cols)))
+	} else if ps.rowNumbers {
+		num := ps.stats.Rows + 1
+		ps.line(t, cols,
+			append([]interface{}{strconv.Itoa(num)}, r.FmtArgs()...),
+			append([]interface{}{num}, r.fields...),
+			style,
+		)
+	} else {
+		ps.line(t, cols, r.FmtArgs(), r.fields, style)
+	}
+	ps.stats.Rows++
+}
+
+// Prints the footers, the bottom rules and reports the stats.
+func (ps *pass) close() {
+	for _, t := range ps.tables {
+		switch {
+		case t.last == nil:
+		case ps.footer != nil:
+			cols := ps.layout(t, t.head)
+			ps.separate(t, cols)
+			ps.line(t, cols, ps.titleArgs(ps.footer, t.head, ""), nil, "")
+			ps.rule(t, ruleBottom, cols)
+		default:
+			ps.rule(t, ruleBottom, ps.layout(t, t.last))
+		}
+		switch {
+		case t.hidden == 1:
+			ps.write(t, "(1 column hidden)")
+		case t.hidden > 1:
+			ps.write(t, fmt.Sprintf("(%d columns hidden)", t.hidden))
+		}
+	}
+
+	ps.Printing.stats = ps.stats
+	if ps.statsHook != nil {
+		ps.statsHook(ps.stats)
+	}
+}
+
+// Returns the columns of s to print with. The header and the footer widen the auto-width columns
+// of the table's first schema without touching s.
+func (ps *pass) layout(t *table, s *ColumnSchema) []Column {
+	base := s.cols
+	if m, ok := ps.measured[s]; ok {
+		base = m
+	}
+	headed := (ps.header != nil || ps.footer != nil) && s == t.head
+	if !headed && ps.fitWidth <= 0 && ps.newlines == NewlinesRaw && !ps.rowNumbers && !ps.plain && ps.columns == nil {
+		return base
+	}
+
+	var cols []Column
+	if ps.rowNumbers {
+		num := Column{width: ps.numWidth}
+		num.pad.fixed = true
+		cols = append(cols, num)
+	}
+	cols = append(cols, base...)
+	for i, c := range cols {
+		if c.pad.fixed {
+			continue
+		}
+		w := c.width
+		switch {
+		case ps.plain && c.link != "":
+			w = c.linkWidth
+		case ps.newlines == NewlinesSplit:
+			w = c.lineWidth
+		case ps.newlines == NewlinesEscape:
+			w = c.escWidth
+		}
+		if w < c.min {
+			w = c.min
+		}
+		cols[i].width = w
+	}
+	for i := range s.cols {
+		if !ps.shows(s, i) {
+			cols[i+ps.numCols()].hidden = true
+		}
+	}
+	if headed {
+		for _, titles := range [][]interface{}{ps.headerOf(s), ps.footer} {
+			if titles == nil {
+				continue
+			}
+			for i, a := range ps.titleArgs(titles, s, "") {
+				if w := ps.runeWidths.strWidth(a.(string)); !cols[i].pad.fixed && w > cols[i].width {
+					cols[i].width = w
+				}
+			}
+		}
+	}
+	if ps.fitWidth > 0 {
+		if n := ps.hide(cols); n > t.hidden {
+			t.hidden = n
+		}
+		ps.fit(cols)
+	}
+	return cols
+}
+
+// Hides columns having priorities, the lowest first and the rightmost among equals, until lines
+// fit fitWidth with weighted columns at their floors. Returns the number of hidden columns.
+func (ps *pass) hide(cols []Column) int {
+	hidden := 0
+	for {
+		w := ps.lineWidth(cols)
+		for _, c := range cols {
+			if c.weight > 0 && !c.hidden {
+				w -= c.width - c.min
+			}
+		}
+		if w <= ps.fitWidth {
+			return hidden
+		}
+
+		k := -1
+		for i, c := range cols {
+			if c.priority > 0 && !c.hidden && (k < 0 || c.priority <= cols[k].priority) {
+				k = i
+			}
+		}
+		if k < 0 {
+			return hidden
+		}
+		cols[k].hidden = true
+		hidden++
+	}
+}
+
+// Distributes the width left by the unweighted columns to the weighted ones by their weights,
+// so that lines are exactly fitWidth wide if possible. Weighted columns don't get narrower than
+// their minimum widths given by WithMinWidth(), the others give up the width it takes instead.
+func (ps *pass) fit(cols []Column) {
+	sum := 0
+	for _, c := range cols {
+		if !c.hidden {
+			sum += c.weight
+		}
+	}
+	if sum == 0 {
+		return
+	}
 
-	less lessFn
+	left := ps.fitWidth - ps.lineWidth(cols)
+	for _, c := range cols {
+		if c.weight > 0 && !c.hidden {
+			left += c.width
+		}
+	}
+	if left < 0 {
+		left = 0
+	}
 
-	// A chain of func that generates a CmpFn.
-	chain []func(a interface{}) CmpFn
+	given, last := 0, 0
+	for i := range cols {
+		if cols[i].weight > 0 && !cols[i].hidden {
+			cols[i].width = max(left*cols[i].weight/sum, cols[i].min)
+			cols[i].cut = true
+			given += cols[i].width
+			last = i
+		}
+	}
+	if given <= left {
+		// Rounding leftovers
+		cols[last].width += left - given
+		return
+	}
+	// Taken from the rightmost columns above their floors, lines are wider if all are at theirs
+	for i := last; i >= 0 && given > left; i-- {
+		if cols[i].weight > 0 && !cols[i].hidden {
+			d := min(cols[i].width-cols[i].min, given-left)
+			cols[i].width -= d
+			given -= d
+		}
+	}
 }
 
-// Find a CmpFn that is able to handle (do comparison on) type of a.
-func (s *sortable) matchComparator(a interface{}) (cmp CmpFn, ok bool) {
-	for _, matcher := range s.chain {
-		cmp := matcher(a)
-		if cmp != nil {
-			return cmp, true
+// Returns string representations of the header or footer titles, shrinked or enlarged to fit s.
+// num is the title of the row number column.
+func (ps *pass) titleArgs(titles []interface{}, s *ColumnSchema, num string) []interface{} {
+	args := make([]interface{}, s.count)
+	for i := range args {
+		var a interface{}
+		if i < len(titles) {
+			a = titles[i]
 		}
+		args[i] = MustToString(a)
 	}
-	return nil, false
+	if ps.rowNumbers {
+		args = append([]interface{}{num}, args...)
+	}
+	return args
 }
 
-func (s *sortable) holdsIdenticalType() bool {
+// Returns true if the column i of s is to be printed, i.e. it isn't hidden by WithHidden() and
+// it's given by the indexes or names of WithVisibleColumns().
+func (p *Printing) shows(s *ColumnSchema, i int) bool {
 	switch {
-	case s.count < 2:
-	case s.count >= 2:
-		for i, j := 0, 1; j < s.count; i, j = i+1, j+1 {
-			if reflect.TypeOf(s.cell(i)) != reflect.TypeOf(s.cell(j)) {
-				return false
+	case s.cols[i].hidden:
+		return false
+	case p.columns == nil:
+		return true
+	}
+	for _, k := range p.columns {
+		switch k := k.(type) {
+		case int:
+			if k == i {
+				return true
+			}
+		case string:
+			if s.cols[i].name == k {
+				return true
 			}
 		}
 	}
-	return true
+	return false
 }
 
-func (s *sortable) toLess(cmp CmpFn) lessFn {
-	if s.desc {
-		return func(i, j int) bool { return !cmp(s.cell(i), s.cell(j)) }
-	} else {
-		return func(i, j int) bool { return cmp(s.cell(i), s.cell(j)) }
+// Returns the titles of the header of a table of s, the names of its columns if no titles are
+// given, or nil if there is no header.
+func (p *Printing) headerOf(s *ColumnSchema) []interface{} {
+	if p.header == nil || len(p.header) > 0 {
+		return p.header
+	}
+	titles := make([]interface{}, s.count)
+	for i, c := range s.cols {
+		titles[i] = c.name
 	}
+	return titles
 }
 
-// Cell retrieving method on nth column
-func (s *sortable) cell(row int) interface{} {
-	return s.nodes.cell(s.col, row)
+// Returns the title line, centered over a table of cols if asked to.
+func (ps *pass) titleLine(cols []Column) string {
+	title := ps.title
+	if ps.plain {
+		title = stripANSI(title)
+	}
+	if n := ps.lineWidth(cols) - ps.runeWidths.strWidth(title); ps.titleCentered && n > 0 {
+		return strings.Repeat(" ", n/2) + title
+	}
+	return title
 }
 
-func (s *sortable) Len() int {
-	return s.count
+// Writes a marker of hidden descendants.
+func (ps *pass) marker(t *table, hidden int) {
+	ps.write(t, ps.markerText(hidden))
 }
 
-func (s *sortable) Swap(i, j int) {
-	s.nodes[j], s.nodes[i] = s.nodes[i], s.nodes[j]
+// Returns the marker of hidden descendants, ASCII only in plain mode.
+func (p *Printing) markerText(hidden int) string {
+	ellipsis := "…"
+	if p.plain {
+		ellipsis = "..."
+	}
+	if hidden == 1 {
+		return ellipsis + " 1 hidden descendant"
+	}
+	return fmt.Sprintf("%s %d hidden descendants", ellipsis, hidden)
 }
 
-func (s *sortable) Less(i, j int) bool {
-	return s.less(i, j)
+// Draws a horizontal rule, or a line of dashes without borders.
+func (ps *pass) separate(t *table, cols []Column) {
+	if ps.border != nil {
+		ps.rule(t, ruleMid, cols)
+	} else {
+		ps.write(t, ps.dashes(cols))
+	}
 }
 
-// []*Node is a 2-dimensions slice. (table) And to sort the table's rows, we need:
-//
-// 1. compare in what type of the field value in which column.
-// 2. all values in that column must be in identical type.
-// 3. if we can sort the type of that field value.
-func createSortableOn(column int, ns []*Node, opts ...SortOpt) (*sortable, error) {
-	s := &sortable{
-		nodes: nodes(ns),
-		col:   column,
-		count: len(ns),
-
-		// Set up a fallback Less(), so that an incidental call to Less() won't panic on an unit Sortable instance.
-		less: func(i, j int) bool { return true },
-	}
-	for _, opt := range opts {
-		opt(s)
+// Returns a line of dashes under each column.
+func (ps *pass) dashes(cols []Column) string {
+	var cells []string
+	for _, c := range cols {
+		if !c.hidden {
+			cells = append(cells, strings.Repeat("-", c.width))
+		}
 	}
-	// Put the default CmpFn finder.
-	s.chain = append(s.chain, MatchCmp)
+	return strings.Join(cells, ps.colSep)
+}
 
-	if s.count > 0 {
-		if !s.holdsIdenticalType() {
-			return nil, fmt.Errorf("createSortableOn: column %d doesn't contain identical value type", column)
+// Prints args in cols, wrapped cells continue on the following lines.
+// fields are the raw values of args, nil for the header. style applies to the whole line.
+func (ps *pass) line(t *table, cols []Column, args []interface{}, fields []interface{}, style Style) {
+	var (
+		parts  = make([][]string, len(cols))
+		styles = make([]Style, len(cols))
+		links  = make([]string, len(cols))
+		height = 1
+	)
+	for i, c := range cols {
+		if c.hidden {
+			continue
 		}
-
-		cmp, ok := s.matchComparator(s.cell(0))
-		if !ok {
-			return nil, fmt.Errorf("createSortableOn: don't know how to sort %s", reflect.TypeOf(s.cell(0)))
+		if col := i - ps.numCols(); ps.cellStyler != nil && fields != nil && !ps.plain && col >= 0 {
+			styles[i] = ps.cellStyler(col, ps.stats.Rows, fields[i])
+		}
+		if fields != nil && styles[i] == "" && !ps.plain {
+			if _, ok := fields[i].(error); ok {
+				styles[i] = ps.errorStyle
+			}
 		}
-		s.less = s.toLess(cmp)
-	}
 
-	return s, nil
-}
+		s := args[i].(string)
+		if fields != nil {
+			links[i] = c.href(s)
+		}
+		if ps.plain {
+			s = stripANSI(s)
+			if links[i] != "" {
+				s, links[i] = links[i], ""
+			}
+		}
 
-type SortOpt func(*sortable)
+		lines := []string{s}
+		switch ps.newlines {
+		case NewlinesSplit:
+			lines = splitLines(s)
+		case NewlinesEscape:
+			lines[0] = escapeNewlines(s)
+		}
 
-func WithDescending() SortOpt {
-	return func(s *sortable) {
-		s.desc = true
+		wrapped, cut := false, false
+		for _, l := range lines {
+			switch {
+			case c.wrap > 0 && c.width > 0:
+				ls := ps.runeWidths.wrapText(l, c.width)
+				wrapped = wrapped || len(ls) > 1
+				parts[i] = append(parts[i], ls...)
+			case c.cut && ps.runeWidths.strWidth(l) > c.width:
+				parts[i] = append(parts[i], ps.runeWidths.truncate(l, c.width))
+				cut = true
+			default:
+				parts[i] = append(parts[i], l)
+			}
+		}
+		if wrapped {
+			ps.stats.Wraps++
+		}
+		if cut {
+			ps.stats.Truncations++
+		}
+		if len(parts[i]) > height {
+			height = len(parts[i])
+		}
 	}
-}
 
-// Multiple matcher functions can be provided as input.
-// The method executes them in order until a matcher can handle the current comparing type.
-// A finder should look like this:
-//   func(a interface{}) {
-//     // you can do type switch on a to find a exact type of the input value,
-//     // or simply ignores it if you know in advance the field type you are comparing to.
-//     return func(a, b interface{}) { return a.(int) < b.(int) }
-//   }
-// See MatchCmp() to learn how to write a matcher.
-func WithCmpMatchers(m ...func(interface{}) CmpFn) SortOpt {
-	return func(s *sortable) {
-		s.chain = append(s.chain, m...)
+	if fields != nil && ps.stats.Rows%2 == 1 && !ps.plain {
+		style = ps.zebra.With(style)
 	}
-}
 
-// The default CmpFn matcher used in createSortableOn(). It uses type switch to find the type it can compare.
-// It currently supports only types of string, int or time.Time.
-func MatchCmp(a interface{}) CmpFn {
-	var out CmpFn
-	switch a.(type) {
-	case string:
-		out = func(a, b interface{}) bool { return a.(string) < b.(string) }
-	case int:
-		out = func(a, b interface{}) bool { return a.(int) < b.(int) }
-	case time.Time:
-		out = func(a, b interface{}) bool { return a.(time.Time).Before(b.(time.Time)) }
+	cells := make([]string, 0, len(cols))
+	for k := 0; k < height; k++ {
+		cells = cells[:0]
+		for i, c := range cols {
+			if c.hidden {
+				continue
+			}
+			s := ""
+			if k < len(parts[i]) {
+				s = hyperlink(links[i], styles[i].apply(parts[i][k]))
+			}
+			cells = append(cells, c.fill(s, ps.runeWidths))
+		}
+
+		var str string
+		if b := ps.border; b != nil {
+			str = b.Vertical + " " + strings.Join(cells, " "+b.Vertical+" ") + " " + b.Vertical
+		} else {
+			str = strings.Join(cells, ps.colSep)
+		}
+		if !ps.plain {
+			str = style.applyLine(str)
+		}
+		ps.write(t, str)
 	}
-	return out
 }
 
-// Algorithm for printing.
-type Printing struct {
-	writer    io.Writer
-	colSep    string
-	colSepLen int
-	lineBrk   string
+// Returns the number of columns prepended by Printing.
+func (ps *pass) numCols() int {
+	if ps.rowNumbers {
+		return 1
+	}
+	return 0
 }
 
-// Do nothing if n is nil.
-func (p *Printing) RunNode(n *Node) {
-	if n == nil {
-		return
+// Returns the width of a line printed with cols.
+func (ps *pass) lineWidth(cols []Column) int {
+	w, n := 0, 0
+	for _, c := range cols {
+		if !c.hidden {
+			w += c.width
+			n++
+		}
 	}
-	if n.IsNotRoot() {
-		// only root has no *Row
-		p.RunRow(n.Row())
+	if b := ps.border; b != nil {
+		return w + 2*n + (n+1)*ps.runeWidths.strWidth(b.Vertical)
 	}
-	n.Walk(func(n *Node) {
-		p.RunRow(n.Row())
-	})
+	return w + (n-1)*ps.runeWidths.strWidth(ps.colSep)
 }
 
-// Do nothing if r is nil or there is no columns to print.
-func (p *Printing) RunRow(r *Row) {
-	if r == nil {
-		return
-	}
-
-	str := ""
-	r.EachFmtStr(func(s string) {
-		str += p.colSep
-		str += s
-	})
-
-	if str == "" {
-		// Means no columns to print, will panic fmt.Printf if r.FmtArgs() isn't nil
+func (ps *pass) rule(t *table, kind ruleKind, cols []Column) {
+	if ps.border == nil {
 		return
 	}
+	ps.write(t, ps.border.rule(kind, cols))
+}
 
-	if p.colSepLen > 0 {
-		str = str[p.colSepLen:]
-	}
-	if p.lineBrk != "" {
-		str += p.lineBrk
-	}
-
-	fmt.Fprintf(p.writer, str, r.FmtArgs()...)
+// Writes a physical line.
+func (ps *pass) write(t *table, line string) {
+	n, _ := io.WriteString(t.w, line+ps.lineBrk)
+	ps.stats.Bytes += n
+	ps.stats.Lines++
 }
 
 // Printing options are:
@@ -659,6 +2897,46 @@ func (p *Printing) RunRow(r *Row) {
 // WithLineBrk(string): set line break. Defaults to "\n".
 //
 // WithWriter(io.Writer): set writer. Defaults to os.Stdout.
+//
+// WithNodeWriter(func(*Node) io.Writer): pick a writer per node in RunNode.
+//
+// WithPlain(): strip styling and decorations, separate columns by a single space.
+//
+// WithBorderStyle(BorderStyle): draw table borders.
+//
+// WithHeader(...interface{}): print a header line above the table.
+//
+// WithFooter(...interface{}): print a footer line below the table.
+//
+// WithVisibleColumns(...interface{}): print only some columns, by indexes or names.
+//
+// WithTitle(string), WithCenteredTitle(string): print a caption above the table.
+//
+// WithStatsHook(func(Stats)): get the counters after each run.
+//
+// WithFitWidth(int): distribute a line width to weighted columns.
+//
+// WithCellStyler(CellStyler): style cells by their values.
+//
+// WithZebra(Style): style every other row.
+//
+// WithRowStyler(RowStyler): style rows by their nodes.
+//
+// WithErrorStyle(Style): style cells holding errors.
+//
+// WithNewlines(NewlineMode): escape or split cells containing newlines.
+//
+// WithRowNumbers(): prepend a column of row numbers.
+//
+// WithGroupSeparator(): separate subtrees by rules.
+//
+// WithMaxDepth(int): print rows down to a depth.
+//
+// WithDepthMarker(): mark the descendants hidden by WithMaxDepth().
+//
+// WithLeavesOnly(): print only the rows of leaf nodes.
+//
+// WithSkipReceiverRow(): print only the descendants of the node passed to RunNode().
 func NewPrinting(opts ...PrintingOpt) *Printing {
 	p := &Printing{
 		writer:  os.Stdout,
@@ -668,7 +2946,10 @@ func NewPrinting(opts ...PrintingOpt) *Printing {
 	for _, opt := range opts {
 		opt(p)
 	}
-	p.colSepLen = len(p.colSep)
+	if p.plain {
+		p.colSep = " "
+		p.border = nil
+	}
 
 	return p
 }
@@ -678,6 +2959,59 @@ func Print(n *Node, opts ...PrintingOpt) {
 	NewPrinting(opts...).RunNode(n)
 }
 
+// Renders as many lines of n as fit maxBytes, e.g. for chat message limits, with the given
+// printing options. The writer is replaced. If lines are dropped, a notice like
+// "(3 more lines omitted)" takes the last line. Lines are never split, so no partial escape
+// sequences or runes are emitted. Returns an empty string if not even the notice fits.
+func RenderLimited(n *Node, maxBytes int, opts ...PrintingOpt) string {
+	var b strings.Builder
+	p := NewPrinting(append(append([]PrintingOpt(nil), opts...), WithWriter(&b))...)
+	p.RunNode(n)
+
+	out := b.String()
+	if len(out) <= maxBytes {
+		return out
+	}
+
+	lines := []string{out}
+	if p.lineBrk != "" {
+		lines = strings.SplitAfter(out, p.lineBrk)
+		if lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
+	}
+
+	// Keeps the most lines that fit with the notice.
+	size := len(out)
+	for k := len(lines) - 1; k >= 0; k-- {
+		size -= len(lines[k])
+		notice := fmt.Sprintf("(%d more lines omitted)%s", len(lines)-k, p.lineBrk)
+		if size+len(notice) <= maxBytes {
+			return out[:size] + notice
+		}
+	}
+	return ""
+}
+
+// Prints an aligned definition list, e.g. flags help or env summaries, without building a tree:
+//   name:    alice
+//   verbose: true
+// Keys are followed by colons and padded to the widest one. Returns any error encountered.
+func KV(w io.Writer, pairs ...[2]string) error {
+	s := NewSchema(NewColumn(WithLeftAlignment()))
+	keys := make([]*Row, len(pairs))
+	for i, p := range pairs {
+		keys[i] = NewRow(WithRowSchema(s), WithRowData(p[0]+":"))
+	}
+
+	for i, r := range keys {
+		if _, err := io.WriteString(w, s.cols[0].fill(r.fmtArgs[0].(string), nil)+" "+pairs[i][1]+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 type PrintingOpt func(*Printing)
 
 // Set column separator (field separator). Defaults to " ".
@@ -700,3 +3034,171 @@ func WithWriter(w io.Writer) PrintingOpt {
 		p.writer = w
 	}
 }
+
+// Pick a writer per node in RunNode, e.g. to route error rows to os.Stderr.
+// Returning nil falls back to the writer set by WithWriter().
+func WithNodeWriter(fn func(n *Node) io.Writer) PrintingOpt {
+	return func(p *Printing) {
+		p.nodeWriter = fn
+	}
+}
+
+// Strip styling and decorations, separate columns by a single space. The output is plain ASCII
+// layout suitable for screen readers and legacy terminals. It takes precedence over WithColSep()
+// and any decorating option.
+func WithPlain() PrintingOpt {
+	return func(p *Printing) {
+		p.plain = true
+	}
+}
+
+// Print a header line above the table. Auto-width columns are widened to fit the titles.
+// The titles are shrinked or enlarged to fit the schema of the first printed row.
+// Without titles, the names of the columns given by WithColumnName() are the titles.
+func WithHeader(titles ...interface{}) PrintingOpt {
+	return func(p *Printing) {
+		p.header = append([]interface{}{}, titles...)
+	}
+}
+
+// Print a footer line, e.g. totals or counts, pinned below the last row of the table regardless
+// of sorting and separated by a rule, or by dashes without borders. Like the header, auto-width
+// columns are widened to fit, and the cells are shrinked or enlarged to fit the schema of the first
+// printed row.
+func WithFooter(cells ...interface{}) PrintingOpt {
+	return func(p *Printing) {
+		p.footer = cells
+	}
+}
+
+// Print only the columns given by their indexes or by the names given by WithColumnName(), e.g. a
+// narrow view of a wide tree, without rebuilding it. Columns keep their order in the schema, others
+// are skipped. Header and footer titles are still matched to the columns of the schema, and cell
+// stylers still get the column indexes of the schema. Without arguments, no column is printed.
+func WithVisibleColumns(indexesOrNames ...interface{}) PrintingOpt {
+	return func(p *Printing) {
+		p.columns = append([]interface{}{}, indexesOrNames...)
+	}
+}
+
+// Print a caption line above the table. HTML tables get it as their caption.
+func WithTitle(title string) PrintingOpt {
+	return func(p *Printing) {
+		p.title = title
+		p.titleCentered = false
+	}
+}
+
+// Print a caption line centered over the table, by the width of the lines of the table's first
+// schema. A title wider than the table isn't centered. HTML tables get it as their caption.
+func WithCenteredTitle(title string) PrintingOpt {
+	return func(p *Printing) {
+		p.title = title
+		p.titleCentered = true
+	}
+}
+
+// Get the counters after each RunNode() or RunRow() call, e.g. for logging.
+// They are also available by Printing.Stats().
+func WithStatsHook(fn func(Stats)) PrintingOpt {
+	return func(p *Printing) {
+		p.statsHook = fn
+	}
+}
+
+// Prepend a column numbering the rows from 1, nodes without rows aren't counted. The column is as
+// wide as the number of rows to print, its header title is "#". Cell stylers still get the column
+// indexes of the schema.
+func WithRowNumbers() PrintingOpt {
+	return func(p *Printing) {
+		p.rowNumbers = true
+	}
+}
+
+// Draw a horizontal rule between the subtrees of the children of the printed node, or a line of
+// dashes without borders, so that grouped data is visually separated.
+func WithGroupSeparator() PrintingOpt {
+	return func(p *Printing) {
+		p.groupSep = true
+	}
+}
+
+// Print rows in RunNode() down to depth n, where the children of the printed node are at depth 1.
+// n <= 0 means no limit.
+func WithMaxDepth(n int) PrintingOpt {
+	return func(p *Printing) {
+		if n < 0 {
+			n = 0
+		}
+		p.maxDepth = n
+	}
+}
+
+// Print a marker like "… 3 hidden descendants" below the rows whose descendants are hidden by
+// WithMaxDepth().
+func WithDepthMarker() PrintingOpt {
+	return func(p *Printing) {
+		p.depthMarker = true
+	}
+}
+
+// Print only the rows of the nodes without children in RunNode(), skipping the intermediate
+// nodes that only group them. Nodes at the depth limit of WithMaxDepth() count as leaves.
+func WithLeavesOnly() PrintingOpt {
+	return func(p *Printing) {
+		p.leavesOnly = true
+	}
+}
+
+// Print only the descendants in RunNode(), even if the node passed to it has a row, i.e. isn't a root.
+func WithSkipReceiverRow() PrintingOpt {
+	return func(p *Printing) {
+		p.skipReceiver = true
+	}
+}
+
+// How Printing handles cells containing newlines, which break the layout if printed as is.
+type NewlineMode string
+
+const (
+	// Prints newlines as is, the default.
+	NewlinesRaw NewlineMode = ""
+
+	// Prints newlines as the escape sequences "\n" and "\r\n".
+	NewlinesEscape NewlineMode = "escape"
+
+	// Prints each line of a cell on its own physical line, the other cells of the row are padded.
+	NewlinesSplit NewlineMode = "split"
+)
+
+// Set how cells containing newlines are printed. Columns are as wide as the widest line of their
+// cells when splitting, or the widest escaped cell when escaping. Defaults to NewlinesRaw.
+func WithNewlines(m NewlineMode) PrintingOpt {
+	return func(p *Printing) {
+		p.newlines = m
+	}
+}
+
+// Distribute the width of lines to the columns having WithWeight(), after the unweighted columns,
+// separators and borders took theirs. Weighted columns shrink or grow to fit w, cells wider than
+// their columns are cut, or wrapped if the columns have WithWrap(). Columns having WithPriority()
+// are hidden if the rest doesn't fit. w <= 0 means no fitting.
+func WithFitWidth(w int) PrintingOpt {
+	return func(p *Printing) {
+		p.fitWidth = w
+	}
+}
+
+// Override how runes are measured, for terminals or fonts rendering certain symbols at nonstandard
+// widths, e.g. Powerline glyphs or emoji taking one cell. Negative widths are taken as 0. The
+// auto-width columns are measured again with them from the rows to print, the schemas are left
+// alone. m is copied.
+func WithRuneWidthOverride(m map[rune]int) PrintingOpt {
+	c := make(runeWidths, len(m))
+	for r, w := range m {
+		c[r] = max(w, 0)
+	}
+	return func(p *Printing) {
+		p.runeWidths = c
+	}
+}
@@ -1,7 +1,10 @@
 package pprint
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"testing"
 	"time"
@@ -15,6 +18,11 @@ func (t fmtTime) String() string {
 	return time.Time(t).Format("Jan _2 2006")
 }
 
+type errStringer struct{}
+
+func (errStringer) Error() string  { return "error" }
+func (errStringer) String() string { return "stringer" }
+
 func TestMustToString(t *testing.T) {
 	var (
 		tm, _ = time.Parse("2006-01-02", "1989-12-27")
@@ -31,6 +39,10 @@ func TestMustToString(t *testing.T) {
 			"ptr -> %v":        {(*string)(nil), "<nil>"},
 			"time":             {tm, "1989-12-27 00:00:00 +0000 UTC"},
 			"time + Stringer":  {fmtTime(tm), "Dec 27 1989"},
+			"map -> k=v":       {map[string]int{"b": 2, "a": 1}, "a=1 b=2"},
+			"nil map":          {map[string]int(nil), ""},
+			"error":            {errors.New("failed"), "failed"},
+			"error + Stringer": {errStringer{}, "error"},
 		}
 	)
 	for name, test := range tests {
@@ -38,6 +50,59 @@ func TestMustToString(t *testing.T) {
 	}
 }
 
+func TestRegisterStringer(t *testing.T) {
+	assert := assert.New(t)
+
+	saved, _ := stringers.Load().([]func(interface{}) (string, bool))
+	defer stringers.Store(saved)
+
+	type celsius float64
+	RegisterStringer(func(a interface{}) (string, bool) {
+		if c, ok := a.(celsius); ok {
+			return fmt.Sprintf("%.1f°C", float64(c)), true
+		}
+		return "", false
+	})
+	RegisterStringer(func(a interface{}) (string, bool) {
+		if _, ok := a.(celsius); ok {
+			return "second", true
+		}
+		if b, ok := a.(bool); ok && b {
+			return "yes", true
+		}
+		return "", false
+	})
+
+	assert.Equal("21.5°C", MustToString(celsius(21.5)), "in the order of registration")
+	assert.Equal("yes", MustToString(true))
+	assert.Equal("false", MustToString(false), "falls back to the builtins")
+
+	n := NewNode()
+	n.Push(celsius(-3), true)
+	assert.Equal("-3.0°C yes\n", n.String())
+}
+
+func TestFormatMap(t *testing.T) {
+	tests := map[string]struct {
+		in  interface{}
+		out string
+	}{
+		"not a map":       {[]int{1}, ""},
+		"empty":           {map[int]int{}, ""},
+		"int keys":        {map[int]string{10: "x", 9: "y", -1: "z"}, "-1=z 9=y 10=x"},
+		"string keys":     {map[string]interface{}{"b": nil, "a": 1.5}, "a=1.5 b="},
+		"interface keys":  {map[interface{}]int{"b": 1, 2: 2}, "2=2 b=1"},
+		"keys w/o cmp":    {map[float64]bool{1.5: true, 0.5: false}, "0.5=false 1.5=true"},
+		"nested maps":     {map[string]map[string]int{"x": {"b": 2, "a": 1}}, "x=a=1 b=2"},
+		"stringer values": {map[string]fmtTime{"t": fmtTime{}}, "t=Jan  1 0001"},
+	}
+	for name, test := range tests {
+		for i := 0; i < 10; i++ {
+			assert.Equal(t, test.out, FormatMap(test.in), name)
+		}
+	}
+}
+
 func TestColumn(t *testing.T) {
 	type opts = []ColumnOpt
 
@@ -49,12 +114,37 @@ func TestColumn(t *testing.T) {
 		"fixed < 0":        {opts{WithWidth(-20)}, "%0s"},
 		"fixed width":      {opts{WithWidth(20)}, "%20s"},
 		"pad right":        {opts{WithWidth(20), WithLeftAlignment()}, "%-20s"},
+		"center -> %Ns":    {opts{WithWidth(20), WithCenterAlignment()}, "%20s"},
+		"last one wins":    {opts{WithWidth(20), WithCenterAlignment(), WithLeftAlignment()}, "%-20s"},
+		"min width":        {opts{WithMinWidth(8)}, "%8s"},
+		"min width < 0":    {opts{WithMinWidth(-8)}, "%0s"},
+		"fixed over min":   {opts{WithMinWidth(8), WithWidth(2)}, "%2s"},
 	}
 	for name, test := range tests {
 		assert.Equal(t, test.expected, NewColumn(test.colArgs...).String(), name)
 	}
 }
 
+func TestColumnFill(t *testing.T) {
+	type opts = []ColumnOpt
+
+	tests := map[string]struct {
+		colArgs  opts
+		in       string
+		expected string
+	}{
+		"pad left":            {opts{WithWidth(5)}, "ab", "   ab"},
+		"pad right":           {opts{WithWidth(5), WithLeftAlignment()}, "ab", "ab   "},
+		"center":              {opts{WithWidth(6), WithCenterAlignment()}, "ab", "  ab  "},
+		"center odd to right": {opts{WithWidth(5), WithCenterAlignment()}, "ab", " ab  "},
+		"too long":            {opts{WithWidth(1), WithCenterAlignment()}, "ab", "ab"},
+		"no width":            {opts{WithWidth(0)}, "", ""},
+	}
+	for name, test := range tests {
+		assert.Equal(t, test.expected, NewColumn(test.colArgs...).fill(test.in, nil), name)
+	}
+}
+
 func TestRowSchema(t *testing.T) {
 	tests := map[string]struct {
 		in       *Row
@@ -177,6 +267,87 @@ func TestRowEachFmtStr(t *testing.T) {
 	}
 }
 
+func TestRowWithMinWidth(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewSchema(NewColumn(WithMinWidth(4)), NewColumn(WithMinWidth(4), WithLeftAlignment()))
+	a := NewRow(WithRowSchema(s), WithRowData(1, 2))
+	assert.Equal("   1 2   ", a.String())
+
+	NewRow(WithRowSchema(s), WithRowData(123456, 2))
+	assert.Equal("     1 2   ", a.String(), "grows over the floor")
+}
+
+func TestRowWithFormatters(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		trend = func(fields []interface{}, col int) string {
+			switch prev, cur := fields[0].(int), fields[1].(int); {
+			case cur > prev:
+				return "up"
+			case cur < prev:
+				return "down"
+			}
+			return ""
+		}
+		hex = func(v interface{}) string {
+			return fmt.Sprintf("%#x", v)
+		}
+		s = NewSchema(NewColumn(), NewColumn(WithFormatter(hex)), NewColumn(WithRowFormatter(trend)))
+	)
+
+	a := NewRow(WithRowSchema(s), WithRowData(1, 2))
+	b := NewRow(WithRowSchema(s), WithRowData(20, 10, "raw"))
+	c := NewRow(WithRowSchema(s), WithRowData(3, 3))
+
+	assert.Equal([]interface{}{"1", "0x2", "up"}, a.FmtArgs())
+	assert.Equal([]interface{}{"20", "0xa", "down"}, b.FmtArgs())
+	assert.Equal([]interface{}{"3", "0x3", ""}, c.FmtArgs())
+	assert.Equal([]interface{}{20, 10, "raw"}, b.fields, "raw values are kept")
+	assert.Equal(" 1 0x2   up", a.String())
+}
+
+func TestRowWithVerb(t *testing.T) {
+	s := NewSchema(
+		NewColumn(WithWidth(8), WithVerb("%.2f")),
+		NewColumn(WithVerb("%#x"), WithLeftAlignment()),
+		NewColumn(WithVerb("%+d")),
+	)
+	a := NewRow(WithRowSchema(s), WithRowData(3.14159, 255, 7))
+	b := NewRow(WithRowSchema(s), WithRowData(-1.5, 1))
+
+	assert.Equal(t, "    3.14 0xff +7", a.String())
+	assert.Equal(t, "   -1.50 0x1    ", b.String(), "nil field -> empty str")
+}
+
+func TestRowWithDateParsing(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		taipei = time.FixedZone("CST", 8*60*60)
+		s      = NewSchema(NewColumn(WithDateParsing(taipei, "02/01/2006", "2006-01-02 15:04 MST")))
+		data   = []interface{}{" 27/12/1989"}
+	)
+
+	a := NewRow(WithRowSchema(s), WithRowData(data...))
+	b := NewRow(WithRowSchema(s), WithRowData("1989-12-27 08:00 UTC"))
+	c := NewRow(WithRowSchema(s), WithRowData("yesterday"))
+	d := NewRow(WithRowSchema(s), WithRowData(1))
+
+	assert.Equal(time.Date(1989, 12, 27, 0, 0, 0, 0, taipei), a.fields[0])
+	assert.Equal(" 27/12/1989", data[0], "input is untouched")
+	assert.True(time.Date(1989, 12, 27, 8, 0, 0, 0, time.UTC).Equal(b.fields[0].(time.Time)))
+	assert.Equal("yesterday", c.fields[0])
+	assert.Equal(1, d.fields[0])
+
+	n := NewNode(WithColumns(NewColumn(WithDateParsing(nil, "02/01/2006"))))
+	n.Push("01/02/2001")
+	n.Push("31/01/2001")
+	assert.NoError(n.Sort(0))
+	assert.Equal("2001-01-31 00:00:00 +0000 UTC\n2001-02-01 00:00:00 +0000 UTC\n", n.String())
+}
+
 func TestRowEachFmtStrWithSchemaInheritance(t *testing.T) {
 	assert := assert.New(t)
 
@@ -278,6 +449,202 @@ func TestNodeWalk(t *testing.T) {
 	})
 }
 
+func TestNodePath(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode(WithColumns(NewColumn()))
+	users, _ := n.PushNode(NewNode(WithName("users"), WithRow(NewRow(WithRowSchema(n.Schema())))))
+	admins, _ := users.PushNode(NewNode(WithName("admins"), WithRow(NewRow(WithRowColumns(NewColumn())))))
+	row, _ := admins.Push("alice")
+
+	assert.Equal([]*Node{n}, n.Path())
+	assert.Equal([]*Node{n, users, admins, row}, row.Path())
+	assert.Equal("", n.NamePath())
+	assert.Equal("/users/admins", admins.NamePath())
+	assert.Equal("/users/admins/", row.NamePath(), "unnamed nodes")
+}
+
+func TestNodeDepthHeightSize(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode()
+	a, _ := n.Push("a")
+	a1, _ := a.Push("a1")
+	a11, _ := a1.Push("a11")
+	b, _ := n.Push("b")
+
+	tests := map[string]struct {
+		node                *Node
+		depth, height, size int
+	}{
+		"root":   {n, 0, 3, 4},
+		"child":  {a, 1, 2, 2},
+		"middle": {a1, 2, 1, 1},
+		"deep":   {a11, 3, 0, 0},
+		"leaf":   {b, 1, 0, 0},
+	}
+	for name, tc := range tests {
+		assert.Equal(tc.depth, tc.node.Depth(), name)
+		assert.Equal(tc.height, tc.node.Height(), name)
+		assert.Equal(tc.size, tc.node.Size(), name)
+	}
+}
+
+func TestNodeWalkOrders(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode()
+	a, _ := n.Push("a")
+	a1, _ := a.Push("a1")
+	a1.Push("a11")
+	a.Push("a2")
+	b, _ := n.Push("b")
+	b.Push("b1")
+
+	collect := func(walk func(func(*Node))) []string {
+		var seen []string
+		walk(func(c *Node) {
+			seen = append(seen, c.Row().fields[0].(string))
+		})
+		return seen
+	}
+	assert.Equal([]string{"a", "a1", "a11", "a2", "b", "b1"}, collect(n.Walk))
+	assert.Equal([]string{"a", "b", "a1", "a2", "b1", "a11"}, collect(n.WalkLevels))
+	assert.Equal([]string{"a11", "a1", "a2", "a", "b1", "b"}, collect(n.WalkPostOrder))
+	assert.Empty(collect(NewNode().WalkLevels))
+}
+
+func TestNodeWalkControl(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode()
+	a, _ := n.Push("a")
+	a.Push("a1")
+	a.Push("a2")
+	b, _ := n.Push("b")
+	b.Push("b1")
+	n.Push("c")
+
+	visit := func(actions map[string]WalkAction) ([]string, bool) {
+		var seen []string
+		stopped := n.WalkControl(func(c *Node) WalkAction {
+			v := c.Row().fields[0].(string)
+			seen = append(seen, v)
+			return actions[v]
+		})
+		return seen, stopped
+	}
+
+	tests := map[string]struct {
+		actions map[string]WalkAction
+		want    []string
+		stopped bool
+	}{
+		"continue":      {nil, []string{"a", "a1", "a2", "b", "b1", "c"}, false},
+		"skip children": {map[string]WalkAction{"a": WalkSkipChildren}, []string{"a", "b", "b1", "c"}, false},
+		"stop":          {map[string]WalkAction{"a2": WalkStop}, []string{"a", "a1", "a2"}, true},
+	}
+	for name, tc := range tests {
+		seen, stopped := visit(tc.actions)
+		assert.Equal(tc.want, seen, name)
+		assert.Equal(tc.stopped, stopped, name)
+	}
+}
+
+func TestNodeWalkErr(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode()
+	for i := 0; i < 5; i++ {
+		n.Push(i)
+	}
+
+	count := 0
+	assert.NoError(n.WalkErr(context.Background(), func(c *Node) error {
+		count++
+		return nil
+	}))
+	assert.Equal(5, count)
+
+	count = 0
+	assert.EqualError(n.WalkErr(context.Background(), func(c *Node) error {
+		if count++; c.Row().fields[0] == 2 {
+			return fmt.Errorf("failed on %d", 2)
+		}
+		return nil
+	}), "failed on 2")
+	assert.Equal(3, count)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	count = 0
+	assert.Equal(context.Canceled, n.WalkErr(ctx, func(c *Node) error {
+		if count++; count == 2 {
+			cancel()
+		}
+		return nil
+	}))
+	assert.Equal(2, count)
+}
+
+func TestNodeFind(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode()
+	a, _ := n.Push("a", 1)
+	a1, _ := a.Push("a1", 2)
+	n.Push("b", 2)
+
+	second := func(v int) func(*Node) bool {
+		return func(c *Node) bool { return c.Row().fields[1] == v }
+	}
+	assert.Same(a, n.Find(second(1)))
+	assert.Same(a1, n.Find(second(2)), "depth first")
+	assert.Nil(n.Find(second(3)))
+	assert.Nil(a1.Find(second(2)), "descendants only")
+
+	all := n.FindAll(second(2))
+	if assert.Len(all, 2) {
+		assert.Same(a1, all[0])
+		assert.Equal("b", all[1].Row().fields[0])
+	}
+	assert.Empty(n.FindAll(second(3)))
+}
+
+func TestNodeChildren(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode()
+	a, _ := n.Push("a")
+	b, _ := n.Push("b")
+
+	children := n.Children()
+	assert.Equal([]*Node{a, b}, children)
+	children[0] = b
+	assert.Same(a, n.ChildAt(0), "a copy")
+	assert.Same(b, n.ChildAt(1))
+	assert.Nil(n.ChildAt(2))
+	assert.Nil(n.ChildAt(-1))
+	assert.Empty(a.Children())
+}
+
+func TestNodeChild(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode(WithColumns(NewColumn()))
+	users, err := n.PushNode(NewNode(WithName("users"), WithRow(NewRow(WithRowSchema(n.Schema()), WithRowData("users")))))
+	assert.NoError(err)
+	admins, err := users.PushNode(NewNode(WithName("admins"), WithRow(NewRow(WithRowColumns(NewColumn()), WithRowData("admins")))))
+	assert.NoError(err)
+	n.Push("unnamed")
+
+	assert.Equal("users", users.Name())
+	assert.Same(users, n.Child("users"))
+	assert.Same(admins, n.Child("users").Child("admins"))
+	assert.Nil(n.Child("admins"), "children only")
+	assert.Nil(n.Child("groups").Child("admins"), "chained misses")
+	assert.Empty(n.nodes[1].Name())
+}
+
 func TestNodePushNode(t *testing.T) {
 	assert := assert.New(t)
 
@@ -363,7 +730,9 @@ func TestNodePushNode(t *testing.T) {
 		a.Push(1)
 		b := NewNode(WithRow(NewRow()))
 		_, err := a.PushNode(b)
-		assert.EqualError(err, "PushNode: row of the incoming node doesn't match my node schema")
+		assert.EqualError(err, "PushNode: row of the incoming node doesn't match my node schema: expected 1 columns, got 0")
+		_, err = a.PushNode(NewNode(WithRow(NewRow(WithRowSchemaCopy(a.Schema())))))
+		assert.EqualError(err, "PushNode: row of the incoming node doesn't match my node schema: row of another schema of 1 columns")
 	}
 	{
 		// A has schema, B has row with same schema.
@@ -604,99 +973,1804 @@ func TestNodeSortSuccess(t *testing.T) {
 	}
 }
 
-func TestPrintingRunRow(t *testing.T) {
-	type (
-		pOpts = []PrintingOpt
-		rOpts = []RowOpt
-	)
+func TestMatchCmp(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string][2]interface{}{
+		"bool":     {false, true},
+		"int8":     {int8(-1), int8(1)},
+		"int16":    {int16(-1), int16(1)},
+		"int32":    {int32(-1), int32(1)},
+		"int64":    {int64(-1), int64(1)},
+		"uint":     {uint(1), uint(2)},
+		"uint8":    {uint8(1), uint8(2)},
+		"uint16":   {uint16(1), uint16(2)},
+		"uint32":   {uint32(1), uint32(2)},
+		"uint64":   {uint64(1), uint64(2)},
+		"float32":  {float32(0.5), float32(1.5)},
+		"float64":  {0.5, 1.5},
+		"duration": {time.Second, time.Minute},
+	}
+	for name, tc := range tests {
+		cmp := MatchCmp(tc[0])
+		if !assert.NotNil(cmp, name) {
+			continue
+		}
+		assert.True(cmp(tc[0], tc[1]), name)
+		assert.False(cmp(tc[1], tc[0]), name)
+		assert.False(cmp(tc[0], tc[0]), name)
+	}
+	assert.Nil(MatchCmp(1i))
+
+	n := NewNode()
+	for _, d := range []time.Duration{time.Hour, time.Millisecond, time.Minute} {
+		n.Push(d)
+	}
+	assert.NoError(n.Sort(0))
+	assert.Equal("   1ms\n  1m0s\n1h0m0s\n", n.String())
+}
+
+func TestNodeSortByName(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode(WithColumns(NewColumn(WithColumnName("NAME")), NewColumn(WithColumnName("AGE"))))
+	n.Push("bob", 30)
+	n.Push("alice", 40)
+	n.Push("carol", 20)
+
+	assert.Equal([]string{"NAME", "AGE"}, n.Schema().Names())
+	assert.Equal(1, n.Schema().Index("AGE"))
+	assert.Equal(-1, n.Schema().Index("age"))
+
+	assert.NoError(n.SortByName("AGE"))
+	assert.Equal("carol 20\n  bob 30\nalice 40\n", n.String())
+	assert.EqualError(n.SortByName("age"), `SortByName: column "age" doesn't exist`)
+	assert.EqualError(NewNode().SortByName("AGE"), `SortByName: column "AGE" doesn't exist`)
+}
+
+func TestNodeSortWithSortKey(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode()
+	n.Push("bob", 4, 1)
+	n.Push("Alice", 30, 5)
+	n.Push("carol", 5, 1)
+
+	lower := func(r *Row) interface{} { return strings.ToLower(r.fields[0].(string)) }
+	assert.NoError(n.Sort(0, WithSortKey(lower)))
+	assert.Equal("Alice 30 5\n  bob  4 1\ncarol  5 1\n", n.String())
+
+	sum := func(r *Row) interface{} { return r.fields[1].(int) + r.fields[2].(int) }
+	assert.NoError(n.Sort(0, WithSortKey(sum), WithDescending()))
+	assert.Equal("Alice 30 5\ncarol  5 1\n  bob  4 1\n", n.String())
+	assert.NoError(n.Sort(0, WithSortKey(sum)))
+	assert.Equal("  bob  4 1\ncarol  5 1\nAlice 30 5\n", n.String())
+	assert.NoError(n.Sort(-1, WithSortKey(lower)), "col is ignored")
+	assert.Equal("Alice 30 5\n  bob  4 1\ncarol  5 1\n", n.String())
+
+	mixed := func(r *Row) interface{} { return r.fields[r.fields[1].(int)%2] }
+	assert.Error(n.Sort(0, WithSortKey(mixed)))
+}
+
+func TestNodeMetadata(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode()
+	assert.Nil(n.Get("error"))
+
+	c := NewNode(WithRow(NewRow(WithRowColumns(NewColumn()), WithRowData("x"))))
+	c.Set("error", true)
+	c.Set("rank", 1)
+	c.Set("rank", 2)
+	_, err := n.PushNode(c)
+	assert.NoError(err)
+	assert.Equal(true, n.nodes[0].Get("error"), "carried through PushNode")
+	assert.Equal(2, n.nodes[0].Get("rank"))
+
+	for i, v := range []string{"b", "c", "a"} {
+		m, _ := n.Push(v)
+		m.Set("rank", 3-i)
+	}
+	assert.NoError(n.Sort(0, WithNodeSortKey(func(n *Node) interface{} { return n.Get("rank") })))
+	assert.Equal("a\nx\nc\nb\n", n.String())
+}
+
+func TestNodeSortWithNils(t *testing.T) {
+	assert := assert.New(t)
+
+	newNode := func() *Node {
+		n := NewNode()
+		for _, v := range []interface{}{2, nil, 3, 1, nil} {
+			n.Push(v)
+		}
+		return n
+	}
+
+	assert.EqualError(newNode().Sort(0), "createSortableOn: column 0 doesn't contain identical value type")
 
 	tests := map[string]struct {
-		pOpts pOpts
-		rOpts rOpts
-		out   string
+		opts []SortOpt
+		want []interface{}
 	}{
-		"empty row -> ": {
-			pOpts{}, rOpts{}, "",
-		},
-		"1 column empty string -> \n": {
-			pOpts{}, rOpts{WithRowColumns(NewColumn())}, "\n",
-		},
-		"various types": {
-			pOpts{},
-			rOpts{WithRowData(1, "hello", nil, 123.1, (*string)(nil))},
-			"1 hello  123.1 <nil>\n",
-		},
-		"various types without separator": {
-			pOpts{WithColSep("")},
-			rOpts{WithRowData(1, "hello", nil, 123.1, (*string)(nil))},
-			"1hello123.1<nil>\n",
-		},
-		"various types with my separator and no line break": {
-			pOpts{WithColSep("##"), WithLineBrk("")},
-			rOpts{WithRowData(1, "hello", nil, 123.1, (*string)(nil))},
-			"1##hello####123.1##<nil>",
-		},
+		"nils first":            {[]SortOpt{WithNilsFirst()}, []interface{}{nil, nil, 1, 2, 3}},
+		"nils last":             {[]SortOpt{WithNilsLast()}, []interface{}{1, 2, 3, nil, nil}},
+		"nils last, descending": {[]SortOpt{WithNilsLast(), WithDescending()}, []interface{}{3, 2, 1, nil, nil}},
+	}
+	for name, tc := range tests {
+		n := newNode()
+		assert.NoError(n.Sort(0, tc.opts...), name)
+		var got []interface{}
+		for _, c := range n.nodes {
+			got = append(got, c.Row().fields[0])
+		}
+		assert.Equal(tc.want, got, name)
 	}
 
-	for name, test := range tests {
-		var s strings.Builder
-		test.pOpts = append(test.pOpts, WithWriter(&s))
+	n := NewNode()
+	n.Push(nil)
+	n.Push(nil)
+	assert.NoError(n.Sort(0, WithNilsFirst()), "nothing but nils")
 
-		NewPrinting(test.pOpts...).RunRow(NewRow(test.rOpts...))
-		assert.Equal(t, test.out, s.String(), name)
-	}
+	n.Push("a")
+	n.Push(1)
+	assert.Error(n.Sort(0, WithNilsFirst()), "still identical types")
 }
 
-func TestPrintingRunNode(t *testing.T) {
-	var (
-		assert = assert.New(t)
+func TestNodeSortWithStringFallback(t *testing.T) {
+	assert := assert.New(t)
 
-		s strings.Builder
-		p = NewPrinting(WithWriter(&s))
-	)
+	n := NewNode()
+	for _, v := range []interface{}{"b", 2, nil, 1.5, "a"} {
+		n.Push(v)
+	}
+	assert.Error(n.Sort(0))
+	assert.NoError(n.Sort(0, WithStringFallback()))
+	assert.Equal("   \n1.5\n  2\n  a\n  b\n", n.String(), "mixed types")
+	assert.NoError(n.Sort(0, WithStringFallback(), WithNilsLast()))
+	assert.Equal("1.5\n  2\n  a\n  b\n   \n", n.String(), "along with nil placement")
+
+	m := NewNode()
+	for _, v := range []interface{}{[]int{2}, []int{10}, []int{1}} {
+		m.Push(v)
+	}
+	assert.Error(m.Sort(0))
+	assert.NoError(m.Sort(0, WithStringFallback()))
+	assert.Equal("[10]\n [1]\n [2]\n", m.String(), "unknown type")
 
-	{
-		p.RunNode(NewNode())
-		assert.Equal("", s.String(), "empty node prints nothing")
+	o := NewNode()
+	for _, v := range []int{10, 2, 1} {
+		o.Push(v)
 	}
-	{
-		a := NewNode()
-		a.Push()
-		p.RunNode(a)
-		assert.Equal("", s.String(), "node with empty row prints nothing")
+	assert.NoError(o.Sort(0, WithStringFallback()))
+	assert.Equal(" 1\n 2\n10\n", o.String(), "comparable types as usual")
+}
+
+func TestNodeSortWithCaseInsensitive(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode()
+	for _, s := range []string{"banana", "Cherry", "apple", "Banana"} {
+		n.Push(s)
 	}
-	{
-		a := NewNode()
-		a.Push(nil)
-		p.RunNode(a)
-		assert.Equal("\n", s.String(), "1 column node with empty string")
+	assert.NoError(n.Sort(0))
+	assert.Equal("Banana\nCherry\n apple\nbanana\n", n.String(), "case-sensitive by default")
+	assert.NoError(n.Sort(0, WithCaseInsensitive()))
+	assert.Equal(" apple\nBanana\nbanana\nCherry\n", n.String())
+
+	m := NewNode()
+	m.Push(2)
+	m.Push(1)
+	assert.NoError(m.Sort(0, WithCaseInsensitive()), "other types")
+	assert.Equal("1\n2\n", m.String())
+}
+
+func TestNodeSortWithCollation(t *testing.T) {
+	assert := assert.New(t)
+
+	// Sorts accented letters with their base letters.
+	base := strings.NewReplacer("é", "e", "Ö", "O")
+	compare := func(a, b string) int {
+		return strings.Compare(base.Replace(a), base.Replace(b))
 	}
-	{
-		// Complex case with field shrink and descendants
-		s.Reset()
-		a := NewNode()
-		b, _ := a.Push("1", "12", "123")
-		a.Push()
-		a.Push("", "123", "1234", "1234")
-		b.Push("12345", "12345", "12345", "12345")
-		p.RunNode(a)
-		assert.Equal(
-			"    1    12   123\n"+
-				"12345 12345 12345\n"+
-				"                 \n"+
-				"        123  1234\n",
-			s.String(),
-		)
-		s.Reset()
 
-		// Print subtree
-		p.RunNode(b)
-		assert.Equal(
-			"    1    12   123\n"+
-				"12345 12345 12345\n",
-			s.String(),
-		)
+	n := NewNode()
+	for _, s := range []string{"Zebra", "Österreich", "étude", "Paris", "eagle"} {
+		n.Push(s)
+	}
+	assert.NoError(n.Sort(0, WithCollation(compare)))
+	assert.Equal("Österreich\n     Paris\n     Zebra\n     eagle\n     étude\n", n.String())
+}
+
+func TestNodeSortRecursive(t *testing.T) {
+	assert := assert.New(t)
+
+	newTree := func() *Node {
+		n := NewNode()
+		a, _ := n.Push("usr", 10)
+		b, _ := a.Push("lib", 7)
+		b.Push("libm.so", 1)
+		b.Push("libc.so", 2)
+		a.Push("bin", 3)
+		n.Push("etc", 5)
+		return n
 	}
+
+	n := newTree()
+	assert.NoError(n.Sort(0, WithRecursive()))
+	assert.Equal(
+		"    etc  5\n"+
+			"    usr 10\n"+
+			"    bin  3\n"+
+			"    lib  7\n"+
+			"libc.so  2\n"+
+			"libm.so  1\n",
+		n.String(),
+	)
+
+	n = newTree()
+	assert.NoError(n.Sort(1, WithRecursive(), WithDescending()))
+	assert.Equal(
+		"    usr 10\n"+
+			"    lib  7\n"+
+			"libc.so  2\n"+
+			"libm.so  1\n"+
+			"    bin  3\n"+
+			"    etc  5\n",
+		n.String(),
+	)
+
+	n = newTree()
+	n.nodes[0].nodes[0].Push(1, 2)
+	assert.Error(n.Sort(0, WithRecursive()), "mixed types in a subtree")
+}
+
+func TestNodePushAll(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode()
+	n.Push("first", 0)
+	pushed, err := n.PushAll([][]interface{}{{"a", 1}, {"bb", 22}, {"c"}})
+	assert.NoError(err)
+	assert.Len(pushed, 3)
+	assert.Same(n, pushed[0].Parent())
+	assert.Equal(4, n.NodesCount())
+	assert.Equal("first  0\n    a  1\n   bb 22\n    c   \n", n.String())
+
+	pushed, err = n.PushAll(nil)
+	assert.NoError(err)
+	assert.Empty(pushed)
+
+	var (
+		widths []int
+		m      *Node
+	)
+	m = NewNode(WithColumns(NewColumn(WithFormatter(func(v interface{}) string {
+		widths = append(widths, m.Schema().cols[0].width)
+		return MustToString(v)
+	}))))
+	_, err = m.PushAll([][]interface{}{{"aaa"}, {"b"}})
+	assert.NoError(err)
+	assert.Equal([]int{0, 0}, widths, "not measured while pushing")
+	assert.Equal(3, m.Schema().cols[0].width, "measured once pushed")
+
+	d := NewNode(WithDynamicColumns())
+	d.Push("x")
+	_, err = d.PushAll([][]interface{}{{"aa"}, {"bbb", 1}, {"c", 22}})
+	assert.NoError(err)
+	assert.Equal("  x   \n aa   \nbbb  1\n  c 22\n", d.String(), "grown schemas")
+	assert.False(d.Schema().batching)
+}
+
+func TestNodePushAt(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode()
+	n.Push("b")
+	n.Push("c")
+
+	a, err := n.PushAt(0, "a")
+	assert.NoError(err)
+	assert.Same(n, a.Parent())
+	_, err = n.PushAt(3, "d")
+	assert.NoError(err)
+	_, err = n.PushAt(2, "x")
+	assert.NoError(err)
+	assert.Equal("a\nb\nx\nc\nd\n", n.String())
+
+	_, err = n.PushAt(-1, "y")
+	assert.EqualError(err, "PushAt: index -1 out of range")
+	_, err = n.PushAt(6, "y")
+	assert.EqualError(err, "PushAt: index 6 out of range")
+	assert.Equal(5, n.NodesCount())
+
+	pinned := NewNode(WithRow(NewRow(WithRowSchema(n.Schema()), WithRowData("pinned"))))
+	_, err = n.PushNodeAt(0, pinned)
+	assert.NoError(err)
+	assert.Same(pinned, n.nodes[0])
+	_, err = n.PushNodeAt(9, NewNode())
+	assert.EqualError(err, "PushNodeAt: index 9 out of range")
+	_, err = n.PushNodeAt(0, NewNode(WithRow(NewRow(WithRowData("z")))))
+	assert.Error(err)
+	assert.Equal(6, n.NodesCount())
+}
+
+func TestNodeRemove(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode()
+	a, _ := n.Push("a")
+	b, _ := n.Push("b")
+	b1, _ := b.Push("b1")
+	c, _ := n.Push("c")
+
+	assert.NoError(n.Remove(a))
+	assert.Nil(a.Parent())
+	assert.Equal(" b\nb1\n c\n", n.String())
+	assert.EqualError(n.Remove(a), "Remove: not a child of the node")
+	assert.EqualError(n.Remove(b1), "Remove: not a child of the node", "children only")
+
+	b.Detach()
+	assert.Nil(b.Parent())
+	assert.Equal(" c\n", n.String())
+	assert.Equal("b1\n", b.String(), "keeps descendants")
+	assert.Same(b, b1.Parent())
+
+	n.Detach()
+	assert.Same(n, c.Parent())
+}
+
+func TestNodeMoveTo(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode()
+	todo, _ := n.Push("todo")
+	done, _ := n.Push("done")
+	a, _ := todo.Push("a")
+	b, _ := todo.Push("b")
+
+	assert.NoError(a.MoveTo(done))
+	assert.Same(done, a.Parent())
+	assert.Equal("todo\n   b\ndone\n   a\n", n.String())
+
+	assert.EqualError(todo.MoveTo(todo), "MoveTo: can't move a node under itself")
+	assert.EqualError(n.MoveTo(a), "MoveTo: can't move a node under itself")
+	assert.EqualError(b.MoveTo(nil), "MoveTo: nil parent")
+
+	other := NewNode(WithColumns(NewColumn(), NewColumn()))
+	assert.EqualError(b.MoveTo(other), "PushNode: row of the incoming node doesn't match my node schema: expected 2 columns, got 1")
+	assert.Same(todo, b.Parent(), "stays on errors")
+	assert.Equal("todo\n   b\ndone\n   a\n", n.String())
+
+	root := NewNode()
+	assert.NoError(done.MoveTo(root), "adopts the schema")
+	assert.Same(n.Schema(), root.Schema())
+	assert.Equal("done\n   a\n", root.String())
+}
+
+func TestNodeSetRow(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode(WithColumns(NewColumn(WithMinWidth(3)), NewColumn(WithWidth(2))))
+	a, _ := n.Push("long value", 1)
+	b, _ := a.Push("b", 2)
+
+	assert.NoError(a.SetRow(NewRow(WithRowSchema(n.Schema()), WithRowData("a", 10))))
+	assert.Equal("  a 10\n  b  2\n", n.String(), "auto widths shrink to the min, fixed widths stay")
+
+	assert.NoError(b.SetRow(NewRow(WithRowSchema(n.Schema()), WithRowData("wider", 3))))
+	assert.Equal("    a 10\nwider  3\n", n.String())
+
+	assert.EqualError(a.SetRow(nil), "SetRow: nil row")
+	assert.EqualError(a.SetRow(NewRow(WithRowData("x"))), "SetRow: row doesn't match the schema of the parent")
+	assert.Equal("    a 10\nwider  3\n", n.String())
+
+	root := NewNode()
+	assert.NoError(root.SetRow(NewRow(WithRowData("root"))), "any schema without a parent")
+}
+
+func TestNodeLimit(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode()
+	for _, v := range []int{3, 1, 4, 5, 2} {
+		n.Push(v)
+	}
+	assert.Nil(n.Limit(-1))
+	assert.Nil(n.Limit(5))
+	assert.Equal(5, n.NodesCount())
+
+	rest := n.Limit(3)
+	assert.Equal("3\n1\n4\n", n.String())
+	if assert.Len(rest, 2) {
+		assert.Equal([]interface{}{5}, rest[0].Row().fields)
+		assert.Equal([]interface{}{2}, rest[1].Row().fields)
+		assert.Nil(rest[0].Parent())
+	}
+
+	assert.NoError(n.Sort(0, WithDescending(), WithLimit(2)))
+	assert.Equal("4\n3\n", n.String())
+
+	m := NewNode()
+	for i := 1; i <= 3; i++ {
+		c, _ := m.Push(i * 10)
+		for j := 1; j <= 3; j++ {
+			c.Push(i*10 + j)
+		}
+	}
+	assert.NoError(m.Sort(0, WithDescending(), WithLimit(1), WithRecursive()))
+	assert.Equal("30\n33\n", m.String(), "recursive")
+}
+
+func TestNodeClone(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode(WithName("root"))
+	a, _ := n.Push("b", 1)
+	a.Set("k", "v")
+	a.Push("a1", 2)
+	n.Push("a", 3)
+
+	c := n.Clone(false)
+	assert.Equal(n.String(), c.String())
+	assert.Equal("root", c.Name())
+	assert.Equal("v", c.nodes[0].Get("k"))
+	assert.Same(c, c.nodes[0].Parent())
+	assert.Same(c.nodes[0], c.nodes[0].nodes[0].Parent())
+	assert.Same(n.Schema(), c.Schema(), "shares schemas")
+
+	assert.NoError(c.Sort(0))
+	c.nodes[0].Set("k", "w")
+	assert.Equal(" b 1\na1 2\n a 3\n", n.String(), "the original is untouched")
+	assert.Equal("v", a.Get("k"))
+
+	d := n.Clone(true)
+	assert.NotSame(n.Schema(), d.Schema())
+	assert.Same(d.Schema(), d.nodes[0].Row().Schema())
+	assert.Same(d.Schema(), d.nodes[0].nodes[0].Row().Schema(), "copies each schema once")
+	d.Push("wider", 4)
+	assert.Equal(" b 1\na1 2\n a 3\n", n.String(), "widths of the original stay")
+}
+
+func TestNodeReverse(t *testing.T) {
+	assert := assert.New(t)
+
+	newNode := func() *Node {
+		n := NewNode()
+		m, _ := n.Push(1)
+		m.Push(11)
+		m.Push(12)
+		n.Push(2)
+		n.Push(3)
+		return n
+	}
+
+	n := newNode()
+	n.Reverse(false)
+	assert.Equal(" 3\n 2\n 1\n11\n12\n", n.String())
+
+	n = newNode()
+	n.Reverse(true)
+	assert.Equal(" 3\n 2\n 1\n12\n11\n", n.String(), "recursive")
+
+	NewNode().Reverse(true)
+}
+
+func TestNodeMigrateSchema(t *testing.T) {
+	assert := assert.New(t)
+
+	newTree := func() (*Node, *Node) {
+		root := NewNode()
+		a, _ := root.Push("alice", 30, "admin")
+		a.Push("bob", 4, "guest")
+
+		sub := NewNode(WithColumns(NewColumn()))
+		sub.Push("own schema")
+		root.PushNode(sub)
+		return root, sub
+	}
+
+	{
+		root, sub := newTree()
+		s := NewSchema(NewColumn(), NewColumn(WithLeftAlignment()), NewColumn())
+		assert.NoError(root.MigrateSchema(s, []int{2, 0, -1}))
+
+		assert.Same(s, root.Schema())
+		assert.Same(s, root.nodes[0].Schema())
+		assert.Same(s, root.nodes[0].nodes[0].Row().Schema())
+		assert.Equal([]interface{}{"admin", "alice", nil}, root.nodes[0].Row().fields)
+		assert.Equal([]interface{}{"guest", "bob", ""}, root.nodes[0].nodes[0].Row().FmtArgs())
+		assert.Equal("%-5s", s.cols[1].String())
+		assert.NotSame(s, sub.Schema(), "own schemas are kept")
+		assert.Equal([]interface{}{"own schema"}, sub.nodes[0].Row().FmtArgs())
+	}
+
+	tests := map[string]struct {
+		schema  *ColumnSchema
+		mapping []int
+	}{
+		"nil schema":         {nil, nil},
+		"mapping count":      {NewSchema(NewColumn()), []int{0, 1}},
+		"nonexistent column": {NewSchema(NewColumn()), []int{3}},
+		"negative column":    {NewSchema(NewColumn()), []int{-2}},
+	}
+	for name, test := range tests {
+		root, _ := newTree()
+		old := root.Schema()
+		assert.Error(root.MigrateSchema(test.schema, test.mapping), name)
+		assert.Same(old, root.nodes[0].Row().Schema(), name)
+	}
+	assert.Error(NewNode().MigrateSchema(NewSchema(), nil), "no schema")
+}
+
+func TestNodeAddComputedColumn(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode(WithColumns(NewColumn(WithColumnName("NAME"), WithLeftAlignment()), NewColumn(), NewColumn()))
+	a, _ := n.Push("alice", 3, 4)
+	a.Push("bob", 1, 8)
+	ratio := func(r *Row) interface{} {
+		return float64(r.fields[1].(int)) / float64(r.fields[2].(int))
+	}
+
+	assert.NoError(n.AddComputedColumn("RATIO", ratio, WithPrecision(2)))
+	assert.Equal([]string{"NAME", "", "", "RATIO"}, n.Schema().Names())
+	assert.Equal("alice 3 4 0.75\nbob   1 8 0.12\n", n.String())
+
+	n.Push("carol", 10, 4, "ignored")
+	assert.Equal("alice  3 4 0.75\nbob    1 8 0.12\ncarol 10 4 2.50\n", n.String(), "rows pushed later")
+
+	assert.NoError(n.Sort(3, WithDescending()))
+	assert.Equal("carol 10 4 2.50\nalice  3 4 0.75\nbob    1 8 0.12\n", n.String(), "sortable raw values")
+
+	assert.EqualError(NewNode().AddComputedColumn("X", ratio), "AddComputedColumn: no schema to add to")
+	assert.EqualError(n.AddComputedColumn("X", nil), "AddComputedColumn: nil function")
+
+	shared := NewNode(WithSchema(n.Schema()))
+	shared.Push("dave", 1, 2)
+	assert.NoError(n.AddComputedColumn("DOUBLE", func(r *Row) interface{} { return r.fields[3].(float64) * 2 }))
+	assert.Equal(4, shared.Schema().count, "other trees keep the schema")
+	assert.Equal("dave   1 2 0.50\n", shared.String())
+	assert.NoError(n.Validate())
+}
+
+func TestNodeAppendColumn(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode()
+	a, _ := n.Push("alice", 30)
+	b, _ := n.Push("bob", 4)
+	a.Push("carol", 5)
+
+	assert.NoError(n.AppendColumn(NewColumn(WithLeftAlignment()), []interface{}{"admin", "x", "guest"}))
+	assert.Equal("alice 30 admin\ncarol  5 x    \n  bob  4 guest\n", n.String(), "values in the order of Walk()")
+
+	assert.NoError(a.AppendColumn(NewColumn(), func(c *Node) interface{} { return c.Depth() }))
+	assert.Equal("alice 30 admin 1\ncarol  5 x     2\n  bob  4 guest  \n", n.String(), "only the subtree")
+	assert.Equal([]interface{}{"bob", 4, "guest", nil}, b.Row().fields)
+
+	assert.EqualError(n.AppendColumn(NewColumn(), []interface{}{1}), "AppendColumn: 1 values for 3 rows")
+	assert.EqualError(n.AppendColumn(NewColumn(), []int{1, 2, 3}), "AppendColumn: []int isn't values or a function")
+	assert.EqualError(NewNode().AppendColumn(NewColumn(), nil), "AppendColumn: no schema to append to")
+	assert.Equal(4, n.Schema().count, "untouched on errors")
+
+	shared := NewNode(WithSchema(n.Schema()))
+	shared.Push("dave", 7, "root")
+	assert.NoError(n.AppendColumn(NewColumn(), func(*Node) interface{} { return "new" }))
+	assert.Equal(5, n.Schema().count)
+	assert.Equal(4, shared.Schema().count, "other trees keep the schema")
+	assert.Equal(" dave  7 root   \n", shared.String())
+	assert.NoError(n.Validate())
+}
+
+func TestNodeDropColumn(t *testing.T) {
+	assert := assert.New(t)
+
+	fields := []interface{}{"alice", "secret", 30}
+	n := NewNode(WithColumns(NewColumn(WithColumnName("NAME")), NewColumn(WithColumnName("TOKEN")), NewColumn(WithColumnName("AGE"))))
+	a, _ := n.Push(fields...)
+	a.Push("bob", "hunter2", 4)
+
+	assert.NoError(n.DropColumn(1))
+	assert.Equal([]string{"NAME", "AGE"}, n.Schema().Names())
+	assert.Equal("alice 30\n  bob  4\n", n.String())
+	assert.Equal([]interface{}{"alice", 30}, a.Row().fields)
+	assert.Equal([]interface{}{"alice", "secret", 30}, fields, "input untouched")
+
+	n.Push("carol", 5)
+	assert.Equal("alice 30\n  bob  4\ncarol  5\n", n.String(), "rows pushed later")
+
+	assert.EqualError(n.DropColumn(2), "DropColumn: column 2 doesn't exist")
+	assert.EqualError(n.DropColumn(-1), "DropColumn: column -1 doesn't exist")
+	assert.EqualError(NewNode().DropColumn(0), "DropColumn: no schema to drop from")
+
+	shared := NewNode(WithSchema(n.Schema()))
+	shared.Push("dave", 7)
+	assert.NoError(n.DropColumn(0))
+	assert.Equal([]string{"AGE"}, n.Schema().Names())
+	assert.Equal([]string{"NAME", "AGE"}, shared.Schema().Names(), "other trees keep the schema")
+	shared.RecalculateWidths()
+	assert.Equal("dave 7\n", shared.String())
+	assert.NoError(n.Validate())
+
+	m := NewNode()
+	m.Push(5, 6)
+	assert.NoError(m.AddComputedColumn("SUM", func(r *Row) interface{} {
+		return r.fields[0].(int) + r.fields[1].(int)
+	}))
+	m.Push(1, 2)
+	assert.EqualError(m.DropColumn(0), "DropColumn: computed column 2 reads the fields by their indexes")
+	assert.Equal("5 6 11\n1 2  3\n", m.String(), "untouched")
+	assert.NoError(m.DropColumn(2))
+	assert.NoError(m.DropColumn(0))
+	assert.Equal("6\n2\n", m.String())
+}
+
+func TestColumnSchemaMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	a := NewNode(WithColumns(NewColumn(WithColumnName("NAME"), WithLeftAlignment()), NewColumn(), NewColumn(WithWidth(2))))
+	a.Push("alice", 3, "x")
+	b := NewNode(WithColumns(NewColumn(), NewColumn(WithColumnName("AGE")), NewColumn()))
+	b.Push("bob", 1234, "yyy")
+	b.Push("carol", 5, "z")
+
+	a.Schema().Merge(b.Schema())
+	assert.Equal("alice    3  x\n", a.String())
+	assert.Equal("  bob 1234 yyy\ncarol    5   z\n", b.String(), "fixed widths are kept")
+	assert.Equal([]string{"NAME", "AGE", ""}, a.Schema().Names())
+	assert.Equal([]string{"NAME", "AGE", ""}, b.Schema().Names())
+
+	c := NewNode()
+	c.Push("a-very-long-name")
+	c.Schema().Merge(a.Schema())
+	a.Schema().Merge(c.Schema())
+	assert.Equal("alice               3  x\n", a.String(), "extra columns are left alone")
+	assert.Equal(1, c.Schema().count)
+}
+
+func TestNodeMergeSchema(t *testing.T) {
+	assert := assert.New(t)
+
+	a := NewNode(WithColumns(NewColumn(WithColumnName("NAME"), WithLeftAlignment()), NewColumn()))
+	a.Push("alice", 3)
+	b := NewNode(WithColumns(NewColumn(), NewColumn(), NewColumn(WithColumnName("ROLE"))))
+	b.Push("bob", 1234, "admin")
+	shared := NewNode(WithSchema(a.Schema()))
+	shared.Push("carol", 5)
+
+	assert.NoError(a.MergeSchema(b))
+	assert.Equal(3, a.Schema().count)
+	assert.Equal([]string{"NAME", "", "ROLE"}, a.Schema().Names())
+	assert.Equal([]string{"NAME", "", "ROLE"}, b.Schema().Names())
+	assert.Equal("alice    3      \n", a.String(), "rows get nils")
+	assert.Equal("  bob 1234 admin\n", b.String())
+	assert.NoError(a.Validate())
+	assert.Equal(2, shared.Schema().count, "other trees keep the schema")
+
+	c := NewNode()
+	c.Push("x")
+	assert.NoError(b.MergeSchema(c), "the shorter one is the argument")
+	assert.Equal(3, c.Schema().count)
+	assert.Equal("    x           \n", c.String())
+
+	a.Schema().Freeze()
+	d := NewNode(WithColumns(NewColumn(), NewColumn(), NewColumn(), NewColumn()))
+	assert.EqualError(a.MergeSchema(d), "MergeSchema: schema is frozen")
+	assert.Equal(3, a.Schema().count)
+	assert.EqualError(a.MergeSchema(NewNode()), "MergeSchema: no schema to merge")
+}
+
+func TestColumnSchemaFreeze(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode(WithColumns(NewColumn(WithLeftAlignment()), NewColumn()))
+	a, _ := n.Push("bob", 4)
+	before := a.Row().String()
+	n.Schema().Freeze()
+	assert.True(n.Schema().Frozen())
+
+	b, _ := n.Push("alice", 30)
+	assert.Equal(before, a.Row().String(), "later rows don't widen earlier output")
+	assert.Equal("alice 30", b.Row().String(), "wider cells overflow")
+	assert.Equal("%-3s", n.Schema().cols[0].String())
+
+	other := NewSchema(NewColumn(WithWidth(10)), NewColumn())
+	n.Schema().Merge(other)
+	assert.Equal("%-3s", n.Schema().cols[0].String())
+	assert.Equal("%1s", other.cols[1].String(), "other still widens")
+
+	assert.NoError(b.SetRow(NewRow(WithRowSchema(n.Schema()), WithRowData("al", 3))))
+	assert.Equal("%-3s", n.Schema().cols[0].String())
+
+	assert.EqualError(n.AppendColumn(NewColumn(), []interface{}{1, 2}), "AppendColumn: schema is frozen")
+	assert.EqualError(n.DropColumn(0), "DropColumn: schema is frozen")
+	assert.EqualError(n.AddComputedColumn("X", func(*Row) interface{} { return 1 }), "AddComputedColumn: schema is frozen")
+	assert.True(n.Clone(true).Schema().Frozen())
+}
+
+func TestNodeRecalculateWidths(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode(WithColumns(NewColumn(WithLeftAlignment()), NewColumn(WithMinWidth(3)), NewColumn(WithWidth(6))))
+	n.Push("bob", 4, "x")
+	a, _ := n.Push("alexandra", 12345, "y")
+	c, _ := n.Push("carol", 5, "z")
+	sub := NewNode(WithColumns(NewColumn()))
+	sub.Push("a-long-value")
+	sub.Push("short")
+	c.PushNode(sub)
+
+	assert.NoError(n.Remove(a))
+	assert.NoError(sub.Remove(sub.ChildAt(0)))
+	assert.Equal("bob           4      x\ncarol         5      z\n            \n       short\n", n.String(), "widths only grow")
+
+	sub.RecalculateWidths()
+	assert.Equal("bob     4      x\ncarol   5      z\n     \nshort\n", n.String(), "the whole tree, down to the minimum")
+	assert.Equal("%5s", sub.ChildAt(0).Row().schema.cols[0].String())
+}
+
+func TestColumnSchemaWidthPolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	build := func(p WidthPolicy) *Node {
+		n := NewNode(WithColumns(NewColumn(WithLeftAlignment()), NewColumn()))
+		n.Schema().SetWidthPolicy(p)
+		n.Push("bob", 4)
+		n.Push("alexandra", 12345)
+		n.Push("carol", 5)
+		return n
+	}
+	tests := map[string]struct {
+		policy WidthPolicy
+		out    string
+	}{
+		"grow":   {WidthGrow, "bob           4\ncarol         5\n"},
+		"recalc": {WidthRecalc, "bob   4\ncarol 5\n"},
+	}
+	for name, test := range tests {
+		n := build(test.policy)
+		assert.NoError(n.Remove(n.ChildAt(1)), name)
+
+		var s strings.Builder
+		Print(n, WithWriter(&s))
+		assert.Equal(test.out, s.String(), name)
+	}
+
+	n := build(WidthRecalc)
+	n.Limit(1)
+	assert.Equal("bob 4\n", n.String(), "printed rows only")
+
+	n = NewNode(WithColumns(NewColumn(WithLeftAlignment()), NewColumn()))
+	n.Push("bob", 4)
+	n.Schema().SetWidthPolicy(WidthFixed)
+	n.Push("alice", 30)
+	n.RecalculateWidths()
+	assert.Equal("bob 4\nalice 30\n", n.String(), "snapshot")
+}
+
+func TestColumnSchemaClone(t *testing.T) {
+	assert := assert.New(t)
+
+	a := NewNode(WithColumns(NewColumn(WithColumnName("NAME"), WithLeftAlignment()), NewColumn()))
+	a.Push("bob", 4)
+	a.Schema().SetWidthPolicy(WidthRecalc)
+
+	c := a.Schema().Clone()
+	assert.Equal(a.Schema(), c)
+	assert.NotSame(a.Schema(), c)
+	assert.Nil((*ColumnSchema)(nil).Clone())
+
+	b := NewNode(WithSchemaCopy(a.Schema()))
+	b.Push("alexandra", 12345)
+	assert.Equal("bob 4\n", a.String(), "isolated")
+	assert.Equal("alexandra 12345\n", b.String())
+	assert.Equal([]string{"NAME", ""}, b.Schema().Names())
+
+	r := NewRow(WithRowSchemaCopy(a.Schema()), WithRowData("carol", 30))
+	assert.Equal("carol 30", r.String())
+	assert.Equal("bob 4\n", a.String(), "isolated")
+	_, err := a.PushRow(r)
+	assert.Error(err)
+
+	shared := NewNode(WithSchema(a.Schema()))
+	shared.Push("alexandra", 1)
+	assert.Equal(9, a.Schema().cols[0].width, "shared by pointer")
+}
+
+func TestNodeTranspose(t *testing.T) {
+	assert := assert.New(t)
+
+	root := NewNode(WithColumns(NewColumn(), NewColumn(WithVerb("%.1f")), NewColumn()))
+	a, _ := root.Push("alice", 1.5, "admin")
+	a.Push("bob", 22.25, "guest")
+	sub := NewNode(WithColumns(NewColumn()))
+	sub.Push("skipped")
+	root.PushNode(sub)
+
+	tr := root.Transpose()
+	assert.Equal(
+		"alice   bob \n"+
+			"  1.5  22.2 \n"+
+			"admin guest \n",
+		tr.String(),
+	)
+	assert.Equal(3, tr.nodes[0].Row().schema.count, "the row given to sub is blank")
+	assert.Zero(NewNode().Transpose().NodesCount())
+}
+
+func TestPrintingRunRow(t *testing.T) {
+	type (
+		pOpts = []PrintingOpt
+		rOpts = []RowOpt
+	)
+
+	tests := map[string]struct {
+		pOpts pOpts
+		rOpts rOpts
+		out   string
+	}{
+		"empty row -> ": {
+			pOpts{}, rOpts{}, "",
+		},
+		"1 column empty string -> \n": {
+			pOpts{}, rOpts{WithRowColumns(NewColumn())}, "\n",
+		},
+		"various types": {
+			pOpts{},
+			rOpts{WithRowData(1, "hello", nil, 123.1, (*string)(nil))},
+			"1 hello  123.1 <nil>\n",
+		},
+		"various types without separator": {
+			pOpts{WithColSep("")},
+			rOpts{WithRowData(1, "hello", nil, 123.1, (*string)(nil))},
+			"1hello123.1<nil>\n",
+		},
+		"various types with my separator and no line break": {
+			pOpts{WithColSep("##"), WithLineBrk("")},
+			rOpts{WithRowData(1, "hello", nil, 123.1, (*string)(nil))},
+			"1##hello####123.1##<nil>",
+		},
+	}
+
+	for name, test := range tests {
+		var s strings.Builder
+		test.pOpts = append(test.pOpts, WithWriter(&s))
+
+		NewPrinting(test.pOpts...).RunRow(NewRow(test.rOpts...))
+		assert.Equal(t, test.out, s.String(), name)
+	}
+}
+
+func TestPrintingRunNode(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		s strings.Builder
+		p = NewPrinting(WithWriter(&s))
+	)
+
+	{
+		p.RunNode(NewNode())
+		assert.Equal("", s.String(), "empty node prints nothing")
+	}
+	{
+		a := NewNode()
+		a.Push()
+		p.RunNode(a)
+		assert.Equal("", s.String(), "node with empty row prints nothing")
+	}
+	{
+		a := NewNode()
+		a.Push(nil)
+		p.RunNode(a)
+		assert.Equal("\n", s.String(), "1 column node with empty string")
+	}
+	{
+		// Complex case with field shrink and descendants
+		s.Reset()
+		a := NewNode()
+		b, _ := a.Push("1", "12", "123")
+		a.Push()
+		a.Push("", "123", "1234", "1234")
+		b.Push("12345", "12345", "12345", "12345")
+		p.RunNode(a)
+		assert.Equal(
+			"    1    12   123\n"+
+				"12345 12345 12345\n"+
+				"                 \n"+
+				"        123  1234\n",
+			s.String(),
+		)
+		s.Reset()
+
+		// Print subtree
+		p.RunNode(b)
+		assert.Equal(
+			"    1    12   123\n"+
+				"12345 12345 12345\n",
+			s.String(),
+		)
+	}
+}
+
+func TestPrintingWithNodeWriter(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		stdout, stderr strings.Builder
+	)
+
+	a := NewNode()
+	a.Push("ok", 1)
+	b, _ := a.Push("error", 2)
+	b.Push("ok", 3)
+	a.Push("error", 4)
+
+	Print(a, WithWriter(&stdout), WithNodeWriter(func(n *Node) io.Writer {
+		if n.Row().fields[0] == "error" {
+			return &stderr
+		}
+		return nil
+	}))
+	assert.Equal(
+		"   ok 1\n"+
+			"   ok 3\n",
+		stdout.String(),
+		"nil falls back to the default writer",
+	)
+	assert.Equal(
+		"error 2\n"+
+			"error 4\n",
+		stderr.String(),
+	)
+}
+
+func TestPrintingWithPlain(t *testing.T) {
+	tests := map[string]struct {
+		pOpts []PrintingOpt
+		out   string
+	}{
+		"styles are stripped": {
+			[]PrintingOpt{WithPlain()},
+			"-1 ok\n",
+		},
+		"plain wins over separator": {
+			[]PrintingOpt{WithPlain(), WithColSep(" | ")},
+			"-1 ok\n",
+		},
+		"order doesn't matter": {
+			[]PrintingOpt{WithColSep(" | "), WithPlain()},
+			"-1 ok\n",
+		},
+	}
+
+	for name, test := range tests {
+		var s strings.Builder
+
+		n := NewNode()
+		n.Push("\x1b[31m-1\x1b[0m", "\x1b]8;;https://example.com\x1b\\ok\x1b]8;;\x1b\\")
+		Print(n, append(test.pOpts, WithWriter(&s))...)
+		assert.Equal(t, test.out, s.String(), name)
+	}
+}
+
+func TestPrintingWithHeader(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		s strings.Builder
+	)
+
+	n := NewNode(WithColumns(NewColumn(WithLeftAlignment()), NewColumn(WithWidth(2)), NewColumn()))
+	n.Push("alice", 30, "x")
+	n.Push("bob", 4, "y")
+
+	Print(n, WithWriter(&s), WithHeader("NAME", "AGE"))
+	assert.Equal(
+		"NAME  AGE  \n"+
+			"alice 30 x\n"+
+			"bob    4 y\n",
+		s.String(),
+		"fixed width column won't be widened",
+	)
+	assert.Equal([]string{"%-5s", "%2s", "%1s"}, []string{
+		n.Schema().cols[0].String(),
+		n.Schema().cols[1].String(),
+		n.Schema().cols[2].String(),
+	}, "schema is untouched")
+}
+
+func TestPrintingWithHeaderNames(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		s strings.Builder
+	)
+
+	n := NewNode(WithColumns(NewColumn(WithColumnName("NAME"), WithLeftAlignment()), NewColumn(WithColumnName("AGE")), NewColumn()))
+	n.Push("bob", 7, "x")
+
+	Print(n, WithWriter(&s), WithHeader())
+	assert.Equal("NAME AGE  \nbob    7 x\n", s.String())
+
+	s.Reset()
+	Print(n, WithWriter(&s), WithHeader("N"))
+	assert.Equal("N      \nbob 7 x\n", s.String(), "titles first")
+
+	s.Reset()
+	Print(n, WithWriter(&s))
+	assert.Equal("bob 7 x\n", s.String())
+}
+
+func TestPrintingWithFooter(t *testing.T) {
+	newNode := func() *Node {
+		n := NewNode()
+		n.Push("bob", 4)
+		n.Push("alice", 30)
+		n.Sort(0)
+		return n
+	}
+
+	tests := map[string]struct {
+		pOpts []PrintingOpt
+		out   string
+	}{
+		"dashes": {
+			[]PrintingOpt{WithFooter("total", 34, "extra")},
+			"alice 30\n" +
+				"  bob  4\n" +
+				"----- --\n" +
+				"total 34\n",
+		},
+		"widened": {
+			[]PrintingOpt{WithFooter("", "sum=34"), WithColSep("|")},
+			"alice|    30\n" +
+				"  bob|     4\n" +
+				"-----|------\n" +
+				"     |sum=34\n",
+		},
+		"border and header": {
+			[]PrintingOpt{WithFooter("total", 34), WithHeader("NAME", "AGE"), WithBorderStyle(BorderASCII)},
+			"+-------+-----+\n" +
+				"|  NAME | AGE |\n" +
+				"+-------+-----+\n" +
+				"| alice |  30 |\n" +
+				"|   bob |   4 |\n" +
+				"+-------+-----+\n" +
+				"| total |  34 |\n" +
+				"+-------+-----+\n",
+		},
+	}
+
+	for name, test := range tests {
+		var s strings.Builder
+		p := NewPrinting(append(test.pOpts, WithWriter(&s))...)
+		p.RunNode(newNode())
+		assert.Equal(t, test.out, s.String(), name)
+		assert.Equal(t, 2, p.Stats().Rows, name)
+	}
+
+	var s strings.Builder
+	NewPrinting(WithWriter(&s), WithFooter("total")).RunNode(NewNode())
+	assert.Empty(t, s.String(), "no footer without rows")
+}
+
+func TestPrintingWithTitle(t *testing.T) {
+	newNode := func() *Node {
+		n := NewNode()
+		n.Push("alice", 30)
+		n.Push("bob", 4)
+		return n
+	}
+
+	tests := map[string]struct {
+		pOpts []PrintingOpt
+		out   string
+	}{
+		"left": {
+			[]PrintingOpt{WithTitle("Users")},
+			"Users\n" +
+				"alice 30\n" +
+				"  bob  4\n",
+		},
+		"centered": {
+			[]PrintingOpt{WithCenteredTitle("Users"), WithBorderStyle(BorderASCII)},
+			"    Users\n" +
+				"+-------+----+\n" +
+				"| alice | 30 |\n" +
+				"|   bob |  4 |\n" +
+				"+-------+----+\n",
+		},
+		"wider than the table": {
+			[]PrintingOpt{WithCenteredTitle("All the users")},
+			"All the users\n" +
+				"alice 30\n" +
+				"  bob  4\n",
+		},
+		"plain": {
+			[]PrintingOpt{WithTitle(StyleBold.apply("Users")), WithPlain()},
+			"Users\n" +
+				"alice 30\n" +
+				"  bob  4\n",
+		},
+	}
+
+	for name, test := range tests {
+		var s strings.Builder
+		p := NewPrinting(append(test.pOpts, WithWriter(&s))...)
+		p.RunNode(newNode())
+		assert.Equal(t, test.out, s.String(), name)
+		assert.Equal(t, strings.Count(test.out, "\n"), p.Stats().Lines, name)
+	}
+}
+
+func TestPrintingWithGroupSeparator(t *testing.T) {
+	n := NewNode()
+	a, _ := n.Push("usr", 10)
+	a.Push("bin", 3)
+	a.Push("lib", 7)
+	n.Push("etc", 5)
+	c, _ := n.Push("var", 1)
+	c.Push("log", 1)
+
+	tests := map[string]struct {
+		pOpts []PrintingOpt
+		out   string
+	}{
+		"dashes": {
+			nil,
+			"usr 10\n" +
+				"bin  3\n" +
+				"lib  7\n" +
+				"--- --\n" +
+				"etc  5\n" +
+				"--- --\n" +
+				"var  1\n" +
+				"log  1\n",
+		},
+		"border": {
+			[]PrintingOpt{WithBorderStyle(BorderASCII)},
+			"+-----+----+\n" +
+				"| usr | 10 |\n" +
+				"| bin |  3 |\n" +
+				"| lib |  7 |\n" +
+				"+-----+----+\n" +
+				"| etc |  5 |\n" +
+				"+-----+----+\n" +
+				"| var |  1 |\n" +
+				"| log |  1 |\n" +
+				"+-----+----+\n",
+		},
+	}
+
+	for name, test := range tests {
+		var s strings.Builder
+		p := NewPrinting(append(test.pOpts, WithWriter(&s), WithGroupSeparator())...)
+		p.RunNode(n)
+		assert.Equal(t, test.out, s.String(), name)
+		assert.Equal(t, 6, p.Stats().Rows, name)
+	}
+
+	var s strings.Builder
+	NewPrinting(WithWriter(&s), WithGroupSeparator()).RunNode(a)
+	assert.Equal(t, "usr 10\nbin  3\n--- --\nlib  7\n", s.String(), "groups of a subtree")
+}
+
+func TestPrintingWithMaxDepth(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode()
+	a, _ := n.Push("usr", 10)
+	b, _ := a.Push("lib", 7)
+	b.Push("libc.so", 2)
+	b.Push("libm.so", 1)
+	a.Push("bin", 3)
+	c, _ := n.Push("etc", 5)
+	c.Push("hosts", 1)
+
+	var s strings.Builder
+	p := NewPrinting(WithWriter(&s), WithMaxDepth(1))
+	p.RunNode(n)
+	assert.Equal("    usr 10\n    etc  5\n", s.String(), "widths include hidden rows")
+	assert.Equal(2, p.Stats().Rows)
+
+	s.Reset()
+	p = NewPrinting(WithWriter(&s), WithMaxDepth(2), WithDepthMarker(), WithRowNumbers())
+	p.RunNode(n)
+	assert.Equal(
+		"1     usr 10\n"+
+			"2     lib  7\n"+
+			"… 2 hidden descendants\n"+
+			"3     bin  3\n"+
+			"4     etc  5\n"+
+			"5   hosts  1\n",
+		s.String(),
+	)
+	assert.Equal(Stats{Rows: 5, Lines: 6, Bytes: s.Len()}, p.Stats())
+
+	s.Reset()
+	NewPrinting(WithWriter(&s), WithMaxDepth(1), WithDepthMarker()).RunNode(a)
+	assert.Equal(
+		"    usr 10\n"+
+			"    lib  7\n"+
+			"… 2 hidden descendants\n"+
+			"    bin  3\n",
+		s.String(),
+		"depths are relative to the printed node",
+	)
+
+	s.Reset()
+	NewPrinting(WithWriter(&s), WithMaxDepth(1), WithDepthMarker(), WithPlain()).RunNode(n)
+	assert.Equal("    usr 10\n... 4 hidden descendants\n    etc  5\n... 1 hidden descendant\n", s.String(), "ASCII only in plain mode")
+}
+
+func TestPrintingWithLeavesOnly(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode()
+	a, _ := n.Push("usr", 10)
+	b, _ := a.Push("lib", 7)
+	b.Push("libc.so", 2)
+	a.Push("bin", 3)
+	n.Push("etc", 5)
+
+	var s strings.Builder
+	NewPrinting(WithWriter(&s), WithLeavesOnly()).RunNode(n)
+	assert.Equal(
+		"libc.so  2\n"+
+			"    bin  3\n"+
+			"    etc  5\n",
+		s.String(),
+	)
+
+	s.Reset()
+	NewPrinting(WithWriter(&s), WithLeavesOnly(), WithMaxDepth(2)).RunNode(n)
+	assert.Equal(
+		"    lib  7\n"+
+			"    bin  3\n"+
+			"    etc  5\n",
+		s.String(),
+		"nodes at the depth limit are leaves",
+	)
+
+	s.Reset()
+	NewPrinting(WithWriter(&s), WithLeavesOnly()).RunNode(n.nodes[1])
+	assert.Equal("    etc  5\n", s.String(), "the printed node is a leaf")
+}
+
+func TestPrintingWithSkipReceiverRow(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode()
+	a, _ := n.Push("usr", 10)
+	a.Push("lib", 7)
+	a.Push("bin", 3)
+
+	var s strings.Builder
+	p := NewPrinting(WithWriter(&s), WithSkipReceiverRow())
+	p.RunNode(a)
+	assert.Equal("lib  7\nbin  3\n", s.String())
+
+	s.Reset()
+	p.RunNode(n)
+	assert.Equal("usr 10\nlib  7\nbin  3\n", s.String(), "roots have no rows anyway")
+
+	s.Reset()
+	p.RunNode(a.nodes[0])
+	assert.Empty(s.String())
+}
+
+func TestPrintingWithRowRenderer(t *testing.T) {
+	dashes := func(r *Row, width int) string {
+		return strings.Repeat("-", width)
+	}
+
+	tests := map[string]struct {
+		pOpts []PrintingOpt
+		out   string
+	}{
+		"default": {
+			[]PrintingOpt{WithColSep(" | ")},
+			"alice | 30\n" +
+				"----------\n" +
+				"  bob |  4\n",
+		},
+		"with border": {
+			[]PrintingOpt{WithBorderStyle(BorderASCII)},
+			"+-------+----+\n" +
+				"| alice | 30 |\n" +
+				"--------------\n" +
+				"|   bob |  4 |\n" +
+				"+-------+----+\n",
+		},
+	}
+
+	for name, test := range tests {
+		var s strings.Builder
+
+		n := NewNode()
+		n.Push("alice", 30)
+		_, err := n.PushNode(NewNode(WithRowRenderer(dashes)))
+		assert.NoError(t, err)
+		n.Push("bob", 4)
+
+		Print(n, append(test.pOpts, WithWriter(&s))...)
+		assert.Equal(t, test.out, s.String(), name)
+	}
+}
+
+func TestPrintingStats(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		s      strings.Builder
+		hooked []Stats
+	)
+
+	n := NewNode()
+	n.Push("alice", 30)
+	n.Push()
+	n.Push("bob", 4)
+
+	p := NewPrinting(
+		WithWriter(&s),
+		WithBorderStyle(BorderASCII),
+		WithHeader("NAME", "AGE"),
+		WithStatsHook(func(st Stats) { hooked = append(hooked, st) }),
+	)
+	p.RunNode(n)
+
+	expected := Stats{Rows: 3, Lines: 7, Bytes: len(s.String())}
+	assert.Equal(expected, p.Stats())
+	assert.Equal([]Stats{expected}, hooked)
+
+	s.Reset()
+	p.RunRow(NewRow(WithRowData("x")))
+	expected = Stats{Rows: 1, Lines: 5, Bytes: len(s.String())}
+	assert.Equal(expected, p.Stats(), "counters are reset on each run")
+	assert.Len(hooked, 2)
+}
+
+func TestPrintingWithWrap(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		s strings.Builder
+		p = NewPrinting(WithWriter(&s), WithColSep(" | "))
+	)
+
+	n := NewNode(WithColumns(
+		NewColumn(),
+		NewColumn(WithWrap(10), WithLeftAlignment()),
+		NewColumn(WithWidth(4), WithWrap(1)),
+	))
+	n.Push(1, "the quick brown fox", "ab")
+	n.Push(22, "jumps", "c")
+
+	p.RunNode(n)
+	assert.Equal(
+		" 1 | the quick  |   ab\n"+
+			"   | brown fox  |     \n"+
+			"22 | jumps      |    c\n",
+		s.String(),
+		"fixed width column wraps at its width",
+	)
+	assert.Equal(1, p.Stats().Wraps)
+	assert.Equal(3, p.Stats().Lines)
+	assert.Equal(2, p.Stats().Rows)
+}
+
+func TestPrintingWithNewlines(t *testing.T) {
+	newNode := func() *Node {
+		n := NewNode(WithColumns(
+			NewColumn(),
+			NewColumn(WithLeftAlignment()),
+			NewColumn(WithWrap(3)),
+		))
+		n.Push(1, "first\nsecond", "a")
+		n.Push(22, "x", "abcd\r\nef")
+		return n
+	}
+
+	tests := map[string]struct {
+		mode     NewlineMode
+		expected string
+		lines    int
+	}{
+		"split": {
+			NewlinesSplit,
+			" 1 | first  |   a\n" +
+				"   | second |    \n" +
+				"22 | x      | abc\n" +
+				"   |        |   d\n" +
+				"   |        |  ef\n",
+			5,
+		},
+		"escape": {
+			NewlinesEscape,
+			" 1 | first\\nsecond |   a\n" +
+				"22 | x             | abc\n" +
+				"   |               | d\\r\n" +
+				"   |               | \\ne\n" +
+				"   |               |   f\n",
+			5,
+		},
+	}
+
+	for name, test := range tests {
+		var s strings.Builder
+		p := NewPrinting(WithWriter(&s), WithColSep(" | "), WithNewlines(test.mode))
+		p.RunNode(newNode())
+		assert.Equal(t, test.expected, s.String(), name)
+		assert.Equal(t, test.lines, p.Stats().Lines, name)
+	}
+}
+
+func TestPrintingWithVisibleColumns(t *testing.T) {
+	n := NewNode(WithColumns(
+		NewColumn(WithColumnName("NAME"), WithLeftAlignment()),
+		NewColumn(WithColumnName("AGE")),
+		NewColumn(WithColumnName("EMAIL")),
+	))
+	n.Push("alice", 30, "alice@example.com")
+	n.Push("bob", 4, "bob@example.com")
+
+	tests := map[string]struct {
+		opts []PrintingOpt
+		out  string
+	}{
+		"indexes": {
+			[]PrintingOpt{WithVisibleColumns(0, 1)},
+			"alice 30\nbob    4\n",
+		},
+		"names keep the order of the schema": {
+			[]PrintingOpt{WithVisibleColumns("EMAIL", "NAME"), WithHeader()},
+			"NAME              EMAIL\nalice alice@example.com\nbob     bob@example.com\n",
+		},
+		"unknown ones are ignored": {
+			[]PrintingOpt{WithVisibleColumns("AGE", "PHONE", 5), WithHeader()},
+			"AGE\n 30\n  4\n",
+		},
+		"with row numbers": {
+			[]PrintingOpt{WithVisibleColumns(1), WithRowNumbers()},
+			"1 30\n2  4\n",
+		},
+		"with borders": {
+			[]PrintingOpt{WithVisibleColumns("NAME"), WithBorderStyle(BorderASCII)},
+			"+-------+\n| alice |\n| bob   |\n+-------+\n",
+		},
+	}
+	for name, test := range tests {
+		var s strings.Builder
+		Print(n, append(test.opts, WithWriter(&s))...)
+		assert.Equal(t, test.out, s.String(), name)
+	}
+}
+
+func TestColumnWithHidden(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		s strings.Builder
+	)
+
+	n := NewNode(WithColumns(
+		NewColumn(WithColumnName("NAME"), WithLeftAlignment()),
+		NewColumn(WithColumnName("SEEN")),
+		NewColumn(WithColumnName("UNIX"), WithHidden()),
+	))
+	n.Push("alice", "2 days ago", 1700000000)
+	n.Push("bob", "1 hour ago", 1700170000)
+	n.Push("carol", "3 days ago", 1699900000)
+
+	assert.NoError(n.SortByName("UNIX", WithDescending()))
+	assert.Equal("bob   1 hour ago\nalice 2 days ago\ncarol 3 days ago\n", n.String(), "sorted by hidden data")
+
+	Print(n, WithWriter(&s), WithHeader(), WithBorderStyle(BorderASCII), WithFitWidth(10))
+	assert.Equal(
+		"+-------+------------+\n"+
+			"| NAME  |       SEEN |\n"+
+			"+-------+------------+\n"+
+			"| bob   | 1 hour ago |\n"+
+			"| alice | 2 days ago |\n"+
+			"| carol | 3 days ago |\n"+
+			"+-------+------------+\n",
+		s.String(), "not counted as hidden by fitting")
+
+	s.Reset()
+	Print(n, WithWriter(&s), WithVisibleColumns("NAME", "UNIX"))
+	assert.Equal("bob  \nalice\ncarol\n", s.String(), "never visible")
+}
+
+func TestPrintingWithFitWidth(t *testing.T) {
+	newNode := func() *Node {
+		n := NewNode(WithColumns(
+			NewColumn(),
+			NewColumn(WithWeight(1), WithLeftAlignment()),
+			NewColumn(WithWeight(3), WithWrap(1)),
+		))
+		n.Push(1, "alice", "the quick brown fox")
+		n.Push(2, "bob", "jumps")
+		return n
+	}
+
+	tests := map[string]struct {
+		pOpts []PrintingOpt
+		out   string
+		stats Stats
+	}{
+		"shrink": {
+			[]PrintingOpt{WithFitWidth(16)},
+			"1 ali  the quick\n" +
+				"       brown fox\n" +
+				"2 bob      jumps\n",
+			Stats{Rows: 2, Lines: 3, Truncations: 1, Wraps: 1},
+		},
+		"grow": {
+			[]PrintingOpt{WithFitWidth(30), WithColSep("|")},
+			"1|alice |  the quick brown fox\n" +
+				"2|bob   |                jumps\n",
+			Stats{Rows: 2, Lines: 2},
+		},
+		"no room": {
+			[]PrintingOpt{WithFitWidth(1)},
+			"1  \n" +
+				"2  \n",
+			Stats{Rows: 2, Lines: 2, Truncations: 4},
+		},
+	}
+
+	for name, test := range tests {
+		var s strings.Builder
+		p := NewPrinting(append(test.pOpts, WithWriter(&s))...)
+		p.RunNode(newNode())
+		assert.Equal(t, test.out, s.String(), name)
+
+		test.stats.Bytes = len(test.out)
+		assert.Equal(t, test.stats, p.Stats(), name)
+	}
+
+	n := NewNode(WithColumns(
+		NewColumn(WithLeftAlignment()),
+		NewColumn(WithWeight(1), WithMinWidth(8), WithLeftAlignment()),
+		NewColumn(WithWeight(3), WithLeftAlignment()),
+	))
+	n.Push("xxxxxxxx", "yyyyyyyyyy", "zzzzzzzzzz")
+	var s strings.Builder
+	Print(n, WithWriter(&s), WithFitWidth(15), WithColSep("|"))
+	assert.Equal(t, "xxxxxxxx|yyyyyyyy|\n", s.String(), "min widths are floors")
+
+	s.Reset()
+	Print(n, WithWriter(&s), WithFitWidth(24), WithColSep("|"))
+	assert.Equal(t, "xxxxxxxx|yyyyyyyy|zzzzzz\n", s.String(), "the others give up the width")
+}
+
+func TestPrintingWithPriority(t *testing.T) {
+	newNode := func() *Node {
+		n := NewNode(WithColumns(
+			NewColumn(WithLeftAlignment()),
+			NewColumn(WithPriority(2)),
+			NewColumn(WithPriority(1), WithLeftAlignment()),
+			NewColumn(WithPriority(1), WithLeftAlignment()),
+			NewColumn(WithWeight(1), WithMinWidth(3), WithLeftAlignment()),
+		))
+		n.Push("alice", 30, "alice@example.com", "admin", "running")
+		n.Push("bob", 4, "bob@example.com", "guest", "exited")
+		return n
+	}
+
+	tests := map[string]struct {
+		pOpts []PrintingOpt
+		out   string
+	}{
+		"all shown": {
+			[]PrintingOpt{WithFitWidth(48)},
+			"alice 30 alice@example.com admin running        \n" +
+				"bob    4 bob@example.com   guest exited         \n",
+		},
+		"rightmost of the lowest first": {
+			[]PrintingOpt{WithFitWidth(30)},
+			"alice 30 alice@example.com run\n" +
+				"bob    4 bob@example.com   exi\n" +
+				"(1 column hidden)\n",
+		},
+		"with border": {
+			[]PrintingOpt{WithFitWidth(21), WithBorderStyle(BorderASCII), WithHeader("NAME", "AGE")},
+			"+-------+-----+-----+\n" +
+				"| NAME  | AGE |     |\n" +
+				"+-------+-----+-----+\n" +
+				"| alice |  30 | run |\n" +
+				"| bob   |   4 | exi |\n" +
+				"+-------+-----+-----+\n" +
+				"(2 columns hidden)\n",
+		},
+		"not enough": {
+			[]PrintingOpt{WithFitWidth(5)},
+			"alice run\n" +
+				"bob   exi\n" +
+				"(3 columns hidden)\n",
+		},
+	}
+
+	for name, test := range tests {
+		var s strings.Builder
+		p := NewPrinting(append(test.pOpts, WithWriter(&s))...)
+		p.RunNode(newNode())
+		assert.Equal(t, test.out, s.String(), name)
+		assert.Equal(t, strings.Count(test.out, "\n"), p.Stats().Lines, name)
+	}
+}
+
+func TestPrintingWithRowNumbers(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode()
+	for i := 0; i < 10; i++ {
+		n.Push(fmt.Sprintf("r%d", i), i*i)
+	}
+	n.nodes[0].Push("child", -1)
+
+	var (
+		s      strings.Builder
+		styled []int
+	)
+	p := NewPrinting(WithWriter(&s), WithRowNumbers(), WithHeader("NAME", "SQ"), WithCellStyler(func(col, row int, v interface{}) Style {
+		if row == 0 {
+			styled = append(styled, col)
+		}
+		return ""
+	}))
+	p.RunNode(n)
+	assert.Equal(
+		" #  NAME SQ\n"+
+			" 1    r0  0\n"+
+			" 2 child -1\n"+
+			" 3    r1  1\n"+
+			" 4    r2  4\n"+
+			" 5    r3  9\n"+
+			" 6    r4 16\n"+
+			" 7    r5 25\n"+
+			" 8    r6 36\n"+
+			" 9    r7 49\n"+
+			"10    r8 64\n"+
+			"11    r9 81\n",
+		s.String(),
+	)
+	assert.Equal([]int{0, 1}, styled, "stylers get the columns of the schema")
+
+	s.Reset()
+	p.RunNode(n.nodes[0])
+	assert.Equal(
+		"#  NAME SQ\n"+
+			"1    r0  0\n"+
+			"2 child -1\n",
+		s.String(),
+	)
+
+	s.Reset()
+	NewPrinting(WithWriter(&s), WithRowNumbers()).RunRow(n.nodes[1].Row())
+	assert.Equal("1    r1  1\n", s.String())
+}
+
+func TestPrintingWithLinkTemplate(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode(WithColumns(
+		NewColumn(WithLinkTemplate("https://ci.example.com/build/{value}"), WithLeftAlignment()),
+		NewColumn(),
+	))
+	n.Push("42", "ok")
+	n.Push("a b", "failed")
+	n.Push("", "queued")
+
+	var s strings.Builder
+	NewPrinting(WithWriter(&s), WithHeader("ID")).RunNode(n)
+	assert.Equal(
+		"ID        \n"+
+			"\x1b]8;;https://ci.example.com/build/42\x1b\\42\x1b]8;;\x1b\\      ok\n"+
+			"\x1b]8;;https://ci.example.com/build/a%20b\x1b\\a b\x1b]8;;\x1b\\ failed\n"+
+			"    queued\n",
+		s.String(),
+	)
+
+	s.Reset()
+	NewPrinting(WithWriter(&s), WithPlain()).RunNode(n)
+	assert.Equal(
+		"https://ci.example.com/build/42        ok\n"+
+			"https://ci.example.com/build/a%20b failed\n"+
+			"                                   queued\n",
+		s.String(),
+		"plain mode prints URLs",
+	)
+}
+
+func TestPrintingWithANSI(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		s   strings.Builder
+		red = func(s string) string { return "\x1b[31m" + s + "\x1b[0m" }
+	)
+
+	n := NewNode(WithColumns(NewColumn(), NewColumn(WithLeftAlignment()), NewColumn(WithWeight(1))))
+	n.Push(red("-1"), red("ok"), red("abcdef"))
+	n.Push(123, "fine", "abc")
+	assert.Equal([]string{"%3s", "%-4s", "%6s"}, []string{
+		n.Schema().cols[0].String(),
+		n.Schema().cols[1].String(),
+		n.Schema().cols[2].String(),
+	}, "escapes take no width")
+
+	Print(n, WithWriter(&s), WithFitWidth(13))
+	assert.Equal(
+		" "+red("-1")+" "+red("ok")+"   "+"\x1b[31m"+"abcd"+"\x1b[0m"+"\n"+
+			"123 fine  abc\n",
+		s.String(),
+		"padded around escapes, cut cells are reset",
+	)
+}
+
+func TestPrintingWithWideRunes(t *testing.T) {
+	var s strings.Builder
+
+	n := NewNode(WithColumns(NewColumn(WithLeftAlignment()), NewColumn()))
+	n.Push("日本語", "🍣")
+	n.Push("abc", "x")
+	Print(n, WithWriter(&s), WithBorderStyle(BorderUnicode))
+	assert.Equal(
+		t,
+		"┌────────┬────┐\n"+
+			"│ 日本語 │ 🍣 │\n"+
+			"│ abc    │  x │\n"+
+			"└────────┴────┘\n",
+		s.String(),
+	)
+}
+
+func TestRenderLimited(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode()
+	n.Push("alice", "\x1b[31m日本\x1b[0m")
+	n.Push("bob", "ok")
+	n.Push("carol", "ok")
+	n.Push("dave", "ok")
+	full := "alice \x1b[31m日本\x1b[0m\n" +
+		"  bob   ok\n" +
+		"carol   ok\n" +
+		" dave   ok\n"
+
+	assert.Equal(full, RenderLimited(n, len(full)))
+	assert.Equal(full, RenderLimited(n, 1000))
+	assert.Equal(
+		"alice \x1b[31m日本\x1b[0m\n"+
+			"(3 more lines omitted)\n",
+		RenderLimited(n, len(full)-1),
+	)
+	assert.Equal("(4 more lines omitted)\n", RenderLimited(n, 40))
+	assert.Equal("", RenderLimited(n, 10))
+	assert.Equal(
+		"alice|\x1b[31m日本\x1b[0m\r\n"+
+			"(3 more lines omitted)\r\n",
+		RenderLimited(n, 58, WithColSep("|"), WithLineBrk("\r\n")),
+	)
+}
+
+func TestKV(t *testing.T) {
+	assert := assert.New(t)
+
+	var s strings.Builder
+	assert.NoError(KV(&s, [2]string{"name", "alice"}, [2]string{"verbose", "true"}, [2]string{"日本", "wide"}))
+	assert.Equal(
+		"name:    alice\n"+
+			"verbose: true\n"+
+			"日本:    wide\n",
+		s.String(),
+	)
+
+	s.Reset()
+	assert.NoError(KV(&s))
+	assert.Empty(s.String())
 }
 
 func TestRowString(t *testing.T) {
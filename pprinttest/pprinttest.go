@@ -0,0 +1,180 @@
+// Package pprinttest provides utilities to test code that renders pprint trees, by comparing the
+// output against golden files, or trees used as fixtures against each other.
+package pprinttest
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adios/pprint"
+)
+
+var update = flag.Bool("pprint.update", false, "rewrite golden files of pprinttest.AssertGolden")
+
+// Renders n into a string with the given printing options. The writer is always replaced.
+//
+// A copy of n with its own schemas is rendered, its auto widths measured again from the rows of n
+// and its descendants only, so that the output doesn't depend on rows pushed and removed before, or
+// on other trees sharing the schemas. n is untouched. The copy of a non-root node is pushed to a bare
+// root, so that its own row is printed as Print(n) does.
+func Render(n *pprint.Node, opts ...pprint.PrintingOpt) string {
+	c := n.Clone(true)
+	if n.IsNotRoot() {
+		if _, err := pprint.NewNode(pprint.WithSchema(c.Row().Schema())).PushNode(c); err != nil {
+			panic(err)
+		}
+	}
+	c.RecalculateWidths()
+
+	var b strings.Builder
+	pprint.Print(c, append(append([]pprint.PrintingOpt(nil), opts...), pprint.WithWriter(&b))...)
+	return b.String()
+}
+
+// Compares Render(n, opts...) with the content of the golden file at path, reports a line diff on
+// mismatch. Returns true if they are equal.
+//
+// Run tests with -pprint.update to (re)write the golden files instead.
+func AssertGolden(t testing.TB, path string, n *pprint.Node, opts ...pprint.PrintingOpt) bool {
+	t.Helper()
+
+	got := Render(n, opts...)
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("AssertGolden: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatalf("AssertGolden: %v", err)
+		}
+		return true
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("AssertGolden: %v (run with -pprint.update to create it)", err)
+		return false
+	}
+	if d := Diff(string(want), got); d != "" {
+		t.Errorf("AssertGolden: output differs from %s:\n%s", path, d)
+		return false
+	}
+	return true
+}
+
+// Compares the rows and the structure of two trees, reports a line diff of their outlines on
+// mismatch. Returns true if they are equal.
+//
+// An outline prints the string representations of the rows in the order of Walk(), the first cells
+// indented by depth. Both outlines share the same column widths, so only the differences show up.
+func AssertEqualTrees(t testing.TB, want, got *pprint.Node) bool {
+	t.Helper()
+
+	o := outlines(want, got)
+	if d := Diff(o[0], o[1]); d != "" {
+		t.Errorf("AssertEqualTrees: trees differ:\n%s", d)
+		return false
+	}
+	return true
+}
+
+// Renders the outlines of trees with a shared schema.
+func outlines(trees ...*pprint.Node) []string {
+	var (
+		rows  = make([][][]interface{}, len(trees))
+		count = 0
+	)
+	for i, n := range trees {
+		n.Walk(func(c *pprint.Node) {
+			depth := 0
+			for p := c.Parent(); p != n; p = p.Parent() {
+				depth++
+			}
+
+			var cells []interface{}
+			if r := c.Row(); r != nil {
+				cells = append(cells, r.FmtArgs()...)
+			}
+			if len(cells) == 0 {
+				cells = []interface{}{""}
+			}
+			cells[0] = strings.Repeat("  ", depth) + cells[0].(string)
+
+			rows[i] = append(rows[i], cells)
+			if len(cells) > count {
+				count = len(cells)
+			}
+		})
+	}
+
+	cols := make([]pprint.Column, count)
+	for i := range cols {
+		cols[i] = pprint.NewColumn(pprint.WithLeftAlignment())
+	}
+	s := pprint.NewSchema(cols...)
+
+	nodes := make([]*pprint.Node, len(trees))
+	for i := range trees {
+		nodes[i] = pprint.NewNode(pprint.WithSchema(s))
+		for _, cells := range rows[i] {
+			nodes[i].Push(cells...)
+		}
+	}
+
+	out := make([]string, len(trees))
+	for i, n := range nodes {
+		out[i] = Render(n)
+	}
+	return out
+}
+
+// Returns a line diff of want and got, or an empty string if they are equal. Removed lines are
+// prefixed by "-", added lines by "+" and common lines by " ". Line ends are marked with "$" so
+// that trailing padding is visible.
+func Diff(want, got string) string {
+	if want == got {
+		return ""
+	}
+
+	var (
+		a = strings.Split(want, "\n")
+		b = strings.Split(got, "\n")
+
+		// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+		lcs = make([][]int, len(a)+1)
+	)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var out strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			out.WriteString(" " + a[i] + "$\n")
+			i, j = i+1, j+1
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			out.WriteString("-" + a[i] + "$\n")
+			i++
+		default:
+			out.WriteString("+" + b[j] + "$\n")
+			j++
+		}
+	}
+	return out.String()
+}
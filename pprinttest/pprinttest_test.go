@@ -0,0 +1,128 @@
+package pprinttest
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adios/pprint"
+	"github.com/stretchr/testify/assert"
+)
+
+// Records errors instead of failing the test.
+type fakeT struct {
+	testing.TB
+	errors []string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func newNode() *pprint.Node {
+	n := pprint.NewNode()
+	n.Push("alice", 30)
+	n.Push("bob", 4)
+	return n
+}
+
+func TestRender(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("alice|30\n  bob| 4\n", Render(newNode(), pprint.WithColSep("|")))
+
+	n := newNode()
+	c, _ := n.Push("mallory", 1000)
+	assert.NoError(n.Remove(c))
+	assert.Equal("alice|30\n  bob| 4\n", Render(n, pprint.WithColSep("|")), "widths of the rendered rows only")
+	assert.Equal("  alice   30\n    bob    4\n", n.String(), "n is untouched")
+
+	a := n.ChildAt(0)
+	a.Push("bob", 4)
+	var b strings.Builder
+	pprint.Print(a, pprint.WithWriter(&b))
+	assert.Equal("  alice   30\n    bob    4\n", b.String())
+	assert.Equal("alice 30\n  bob  4\n", Render(a), "the row of a subtree, as Print() does")
+	assert.Equal("  bob  4\n", Render(a, pprint.WithSkipReceiverRow()))
+	assert.Equal(n, a.Parent(), "a is untouched")
+}
+
+func TestAssertGolden(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(AssertGolden(t, filepath.Join("testdata", "node.golden"), newNode()))
+
+	{
+		ft := &fakeT{TB: t}
+		n := newNode()
+		n.Push("carol", 5)
+		assert.False(AssertGolden(ft, filepath.Join("testdata", "node.golden"), n))
+		assert.Equal([]string{
+			"AssertGolden: output differs from " + filepath.Join("testdata", "node.golden") + ":\n" +
+				" alice 30$\n" +
+				"   bob  4$\n" +
+				"+carol  5$\n" +
+				" $\n",
+		}, ft.errors)
+	}
+	{
+		ft := &fakeT{TB: t}
+		assert.False(AssertGolden(ft, filepath.Join("testdata", "missing.golden"), newNode()))
+		assert.Len(ft.errors, 1)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := map[string]struct {
+		want, got string
+		expected  string
+	}{
+		"equal":   {"a\nb", "a\nb", ""},
+		"changed": {"a\nb\nc", "a\nx\nc", " a$\n-b$\n+x$\n c$\n"},
+		"removed": {"a\nb", "a", " a$\n-b$\n"},
+		"spaces":  {"a ", "a", "-a $\n+a$\n"},
+	}
+	for name, test := range tests {
+		assert.Equal(t, test.expected, Diff(test.want, test.got), name)
+	}
+}
+
+func TestAssertEqualTrees(t *testing.T) {
+	assert := assert.New(t)
+
+	newTree := func() *pprint.Node {
+		n := newNode()
+		n.Walk(func(c *pprint.Node) {
+			if c.Row().FmtArgs()[0] == "alice" {
+				c.Push("child", 1)
+			}
+		})
+		return n
+	}
+	assert.True(AssertEqualTrees(t, newTree(), newTree()))
+
+	{
+		ft := &fakeT{TB: t}
+		got := newTree()
+		got.Push("carol", 5)
+		assert.False(AssertEqualTrees(ft, newTree(), got))
+		assert.Equal([]string{
+			"AssertEqualTrees: trees differ:\n" +
+				" alice   30$\n" +
+				"   child 1 $\n" +
+				" bob     4 $\n" +
+				"+carol   5 $\n" +
+				" $\n",
+		}, ft.errors)
+	}
+	{
+		ft := &fakeT{TB: t}
+		got := newNode()
+		got.Push("child", 1)
+		assert.False(AssertEqualTrees(ft, newTree(), got), "same rows, different depths")
+		assert.Len(ft.errors, 1)
+	}
+}
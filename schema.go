@@ -0,0 +1,185 @@
+package pprint
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Serialized form of a ColumnSchema, so that layouts can be saved in config files.
+type schemaJSON struct {
+	Columns []columnJSON `json:"columns"`
+	Policy  string       `json:"policy,omitempty"`
+	Frozen  bool         `json:"frozen,omitempty"`
+}
+
+// Serialized form of a Column. Converting functions, such as formatters, parsers and computed
+// values, aren't part of it.
+type columnJSON struct {
+	Name     string `json:"name,omitempty"`
+	Width    int    `json:"width,omitempty"`
+	Fixed    bool   `json:"fixed,omitempty"`
+	Align    string `json:"align,omitempty"`
+	Min      int    `json:"min,omitempty"`
+	Wrap     int    `json:"wrap,omitempty"`
+	Weight   int    `json:"weight,omitempty"`
+	Priority int    `json:"priority,omitempty"`
+	Hidden   bool   `json:"hidden,omitempty"`
+	Link     string `json:"link,omitempty"`
+}
+
+var widthPolicies = map[WidthPolicy]string{WidthGrow: "", WidthRecalc: "recalc", WidthFixed: "fixed"}
+
+// Encodes the layout of the columns: names, widths, alignments and the other options given by
+// ColumnOpts, except the converting functions, e.g. WithFormatter(). The width policy and the
+// frozen state are encoded as well, e.g.:
+//
+//	{"columns": [{"name": "NAME", "width": 12, "fixed": true, "align": "left"}, {"name": "AGE", "width": 3}], "policy": "recalc"}
+//
+// Auto widths are the widths measured so far, loaded schemas start growing from them.
+func (s *ColumnSchema) MarshalJSON() ([]byte, error) {
+	out := schemaJSON{
+		Columns: make([]columnJSON, s.count),
+		Policy:  widthPolicies[s.policy],
+		Frozen:  s.frozen,
+	}
+	for i, c := range s.cols {
+		cj := columnJSON{
+			Name:     c.name,
+			Width:    c.width,
+			Fixed:    c.pad.fixed,
+			Min:      c.min,
+			Wrap:     c.wrap,
+			Weight:   c.weight,
+			Priority: c.priority,
+			Hidden:   c.hidden,
+			Link:     c.link,
+		}
+		switch {
+		case c.pad.center:
+			cj.Align = "center"
+		case c.pad.right:
+			cj.Align = "left"
+		}
+		out.Columns[i] = cj
+	}
+	return json.Marshal(out)
+}
+
+// Decodes a layout encoded by MarshalJSON() into receiver, replacing its columns. It's meant for
+// new schemas, e.g. new(ColumnSchema), as rows of receiver would no longer match it. Alignments are
+// "left", "center" or "right", the default; policies are "recalc" or "fixed", or empty for
+// WidthGrow. Returns any error encountered, receiver is untouched then.
+func (s *ColumnSchema) UnmarshalJSON(b []byte) error {
+	var in schemaJSON
+	if err := json.Unmarshal(b, &in); err != nil {
+		return err
+	}
+
+	policy, ok := WidthGrow, false
+	for p, name := range widthPolicies {
+		if name == in.Policy {
+			policy, ok = p, true
+		}
+	}
+	if !ok {
+		return fmt.Errorf("UnmarshalJSON: unknown width policy %q", in.Policy)
+	}
+
+	cols := make([]Column, len(in.Columns))
+	for i, cj := range in.Columns {
+		opts := []ColumnOpt{
+			WithColumnName(cj.Name),
+			WithMinWidth(cj.Min),
+			WithWrap(cj.Wrap),
+			WithWeight(cj.Weight),
+			WithPriority(cj.Priority),
+			WithLinkTemplate(cj.Link),
+		}
+		switch cj.Align {
+		case "", "right":
+		case "left":
+			opts = append(opts, WithLeftAlignment())
+		case "center":
+			opts = append(opts, WithCenterAlignment())
+		default:
+			return fmt.Errorf("UnmarshalJSON: column %d: unknown alignment %q", i, cj.Align)
+		}
+		if cj.Fixed {
+			opts = append(opts, WithWidth(cj.Width))
+		}
+		if cj.Hidden {
+			opts = append(opts, WithHidden())
+		}
+
+		cols[i] = NewColumn(opts...)
+		if !cj.Fixed && cj.Width > cols[i].width {
+			cols[i].width = cj.Width
+		}
+	}
+
+	*s = ColumnSchema{cols: cols, count: len(cols), frozen: in.Frozen, policy: policy}
+	return nil
+}
+
+// Returns an error describing how r doesn't fit receiver, e.g. "expected 3 columns, got 2", or nil
+// if r is of receiver. Rows are resized to their schemas silently when created, so a row of another
+// schema of the same columns is a mismatch as well, as PushNode() would refuse it.
+func (s *ColumnSchema) Validate(r *Row) error {
+	switch {
+	case r == nil:
+		return fmt.Errorf("nil row")
+	case r.schema == nil:
+		return fmt.Errorf("row without a schema")
+	case r.schema.count != s.count:
+		return fmt.Errorf("expected %d columns, got %d", s.count, r.schema.count)
+	case r.schema != s:
+		return fmt.Errorf("row of another schema of %d columns", s.count)
+	case len(r.fields) != s.count:
+		return fmt.Errorf("expected %d fields, got %d", s.count, len(r.fields))
+	case len(r.fmtArgs) != s.count:
+		return fmt.Errorf("expected %d string representations, got %d", s.count, len(r.fmtArgs))
+	}
+	return nil
+}
+
+// Checks the rows of receiver's descendants against the schemas of their parents, and returns
+// every mismatch found by ColumnSchema.Validate(), joined in the order of Walk(), or nil. Each one
+// is prefixed by the indexes of the child among its siblings from the children of receiver down,
+// e.g. "child 0.2: expected 3 columns, got 2". Nodes without rows are skipped.
+func (n *Node) Validate() error {
+	var (
+		errs []error
+		walk func(p *Node, path []int)
+	)
+	walk = func(p *Node, path []int) {
+		for i, c := range p.nodes {
+			cp := append(append([]int(nil), path...), i)
+			if c.row != nil {
+				var err error
+				if p.schema == nil {
+					err = fmt.Errorf("parent without a schema")
+				} else {
+					err = p.schema.Validate(c.row)
+				}
+				if err != nil {
+					errs = append(errs, fmt.Errorf("child %s: %w", joinPath(cp), err))
+				}
+			}
+			walk(c, cp)
+		}
+	}
+	walk(n, nil)
+	return errors.Join(errs...)
+}
+
+// Returns path as the indexes joined by dots, e.g. "0.2".
+func joinPath(path []int) string {
+	s := make([]string, len(path))
+	for i, k := range path {
+		s[i] = strconv.Itoa(k)
+	}
+	return strings.Join(s, ".")
+}
@@ -0,0 +1,82 @@
+package pprint
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColumnSchemaJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode(WithColumns(
+		NewColumn(WithColumnName("NAME"), WithWidth(8), WithLeftAlignment()),
+		NewColumn(WithColumnName("AGE"), WithMinWidth(2)),
+		NewColumn(WithColumnName("ROLE"), WithCenterAlignment(), WithWrap(10), WithWeight(2), WithPriority(1)),
+		NewColumn(WithHidden(), WithLinkTemplate("/u/{value}")),
+	))
+	n.Push("alice", 301, "admin", 1)
+	n.Schema().SetWidthPolicy(WidthRecalc)
+
+	b, err := json.Marshal(n.Schema())
+	assert.NoError(err)
+	assert.JSONEq(`{
+		"columns": [
+			{"name": "NAME", "width": 8, "fixed": true, "align": "left"},
+			{"name": "AGE", "width": 3, "min": 2},
+			{"name": "ROLE", "width": 5, "align": "center", "wrap": 10, "weight": 2, "priority": 1},
+			{"width": 1, "hidden": true, "link": "/u/{value}"}
+		],
+		"policy": "recalc"
+	}`, string(b))
+
+	s := new(ColumnSchema)
+	assert.NoError(json.Unmarshal(b, s))
+	b2, err := json.Marshal(s)
+	assert.NoError(err)
+	assert.JSONEq(string(b), string(b2), "round trip")
+
+	m := NewNode(WithSchema(s))
+	m.Push("bob", 4, "guest", 2)
+	assert.Equal("bob        4 guest", m.ChildAt(0).Row().String(), "widths are loaded")
+
+	assert.NoError(json.Unmarshal([]byte(`{"columns": [{"align": "right"}], "frozen": true}`), s))
+	assert.Equal(1, s.count)
+	assert.True(s.Frozen())
+
+	assert.EqualError(json.Unmarshal([]byte(`{"columns": [{}, {"align": "middle"}]}`), s), `UnmarshalJSON: column 1: unknown alignment "middle"`)
+	assert.EqualError(json.Unmarshal([]byte(`{"columns": [], "policy": "shrink"}`), s), `UnmarshalJSON: unknown width policy "shrink"`)
+	assert.Equal(1, s.count, "untouched on errors")
+}
+
+func TestColumnSchemaValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewSchema(NewColumn(), NewColumn(), NewColumn())
+	assert.NoError(s.Validate(NewRow(WithRowSchema(s), WithRowData(1))), "resized rows are of the schema")
+	assert.EqualError(s.Validate(NewRow(WithRowData(1, 2))), "expected 3 columns, got 2")
+	assert.EqualError(s.Validate(NewRow(WithRowSchemaCopy(s))), "row of another schema of 3 columns")
+	assert.EqualError(s.Validate(nil), "nil row")
+
+	short := NewRow(WithRowSchema(s))
+	short.fields = short.fields[:2]
+	assert.EqualError(s.Validate(short), "expected 3 fields, got 2")
+
+	n := NewNode(WithSchema(s))
+	a, _ := n.Push(1, 2, 3)
+	b, _ := a.Push(4, 5, 6)
+	n.Push(7, 8, 9)
+	assert.NoError(n.Validate())
+
+	b.row = NewRow(WithRowData(1, 2))
+	n.nodes[1].row = NewRow(WithRowSchemaCopy(s))
+	err := n.Validate()
+	assert.EqualError(err, "child 0.0: expected 3 columns, got 2\nchild 1: row of another schema of 3 columns")
+	assert.NoError(a.ChildAt(0).Validate(), "only descendants")
+	assert.EqualError(a.Validate(), "child 0: expected 3 columns, got 2")
+
+	orphan := NewNode()
+	orphan.nodes = append(orphan.nodes, &Node{row: NewRow(WithRowData(1)), parent: orphan})
+	assert.EqualError(orphan.Validate(), "child 0: parent without a schema")
+}
@@ -0,0 +1,156 @@
+package pprint
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+)
+
+// Machine formats of Sink.
+type SinkFormat int
+
+const (
+	// Comma-separated values, one record per row.
+	SinkCSV SinkFormat = iota
+
+	// Newline-delimited JSON, one array of values per row, or one object keyed by the names of the
+	// columns if every column is named.
+	SinkNDJSON
+)
+
+// Streams rows in a machine format as they come, in parallel to (or instead of) keeping them in a
+// tree. It's append-only, nothing is buffered but what the format needs, call Flush() when done.
+type Sink struct {
+	w      io.Writer
+	format SinkFormat
+	schema *ColumnSchema
+
+	csv *csv.Writer
+	enc *json.Encoder
+}
+
+// Returns a Sink writing rows of schema s to w. Rows with a different column count are shrinked
+// or enlarged to fit s.
+func NewSink(w io.Writer, format SinkFormat, s *ColumnSchema) *Sink {
+	sk := &Sink{w: w, format: format, schema: s}
+	switch format {
+	case SinkCSV:
+		sk.csv = csv.NewWriter(w)
+	case SinkNDJSON:
+		sk.enc = json.NewEncoder(w)
+	}
+	return sk
+}
+
+// Writes r as one record. CSV gets string representations of the fields, NDJSON gets booleans,
+// numbers, strings, nulls and json.Marshaler as they are, and the string representations for
+// anything else. ANSI escape sequences are stripped from strings.
+// Returns any error encountered.
+func (sk *Sink) WriteRow(r *Row) error {
+	if r == nil {
+		return fmt.Errorf("WriteRow: nil row")
+	}
+
+	var (
+		count  = sk.schema.count
+		fields = resizeSlice(append([]interface{}(nil), r.fields...), count)
+		args   = resizeSlice(append([]interface{}(nil), r.fmtArgs...), count)
+	)
+	switch sk.format {
+	case SinkCSV:
+		record := make([]string, count)
+		for i, a := range args {
+			if a != nil {
+				record[i] = stripANSI(a.(string))
+			}
+		}
+		return sk.csv.Write(record)
+	case SinkNDJSON:
+		values := make([]interface{}, count)
+		for i, a := range args {
+			s := ""
+			if a != nil {
+				s = stripANSI(a.(string))
+			}
+			values[i] = jsonValue(fields[i], s)
+		}
+		if sk.schema.named() {
+			return sk.writeObject(values)
+		}
+		return sk.enc.Encode(values)
+	}
+	return fmt.Errorf("WriteRow: unknown format %d", sk.format)
+}
+
+// Writes the names of the columns given by WithColumnName() as a CSV header record. NDJSON has no
+// header, objects are keyed by the names instead. Returns any error encountered.
+func (sk *Sink) WriteHeader() error {
+	if sk.format != SinkCSV {
+		return nil
+	}
+	return sk.csv.Write(sk.schema.Names())
+}
+
+// Writes values as a JSON object keyed by the names of the columns, in the order of the columns.
+func (sk *Sink) writeObject(values []interface{}) error {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, name := range sk.schema.Names() {
+		k, err := json.Marshal(name)
+		if err != nil {
+			return err
+		}
+		v, err := json.Marshal(values[i])
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.Write(k)
+		b.WriteByte(':')
+		b.Write(v)
+	}
+	b.WriteString("}\n")
+	_, err := sk.w.Write(b.Bytes())
+	return err
+}
+
+// Flushes buffered records to the writer. Returns any error encountered.
+func (sk *Sink) Flush() error {
+	if sk.csv != nil {
+		sk.csv.Flush()
+		return sk.csv.Error()
+	}
+	return nil
+}
+
+// Returns a to be encoded as a JSON value, or s if a has no natural JSON form.
+func jsonValue(a interface{}, s string) interface{} {
+	switch a.(type) {
+	case nil:
+		return nil
+	case json.Marshaler:
+		return a
+	case fmt.Stringer:
+		return s
+	}
+
+	switch v := reflect.ValueOf(a); v.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return a
+	case reflect.String:
+		return stripANSI(v.String())
+	case reflect.Float32, reflect.Float64:
+		if f := v.Float(); !math.IsNaN(f) && !math.IsInf(f, 0) {
+			return a
+		}
+	}
+	return s
+}
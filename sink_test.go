@@ -0,0 +1,79 @@
+package pprint
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSink(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		tm, _ = time.Parse("2006-01-02", "1989-12-27")
+	)
+
+	n := NewNode(WithColumns(NewColumn(), NewColumn(), NewColumn(WithVerb("%.1f"))))
+	rows := [][]interface{}{
+		{"a,b", 1, 1.25},
+		{"\x1b[31mred\x1b[0m", nil, 2.0, "discarded"},
+		{tm, fmtTime(tm), 3.0},
+	}
+
+	var csv, ndjson strings.Builder
+	c := NewSink(&csv, SinkCSV, n.Schema())
+	j := NewSink(&ndjson, SinkNDJSON, n.Schema())
+	for _, row := range rows {
+		m, _ := n.Push(row...)
+		assert.NoError(c.WriteRow(m.Row()))
+		assert.NoError(j.WriteRow(m.Row()))
+	}
+	assert.NoError(c.Flush())
+	assert.NoError(j.Flush())
+
+	assert.Equal(
+		"\"a,b\",1,1.2\n"+
+			"red,,2.0\n"+
+			"1989-12-27 00:00:00 +0000 UTC,Dec 27 1989,3.0\n",
+		csv.String(),
+	)
+	assert.Equal(
+		"[\"a,b\",1,1.25]\n"+
+			"[\"red\",null,2]\n"+
+			"[\"1989-12-27T00:00:00Z\",\"Dec 27 1989\",3]\n",
+		ndjson.String(),
+		"raw values are kept if they have a JSON form",
+	)
+
+	assert.EqualError(c.WriteRow(nil), "WriteRow: nil row")
+}
+
+func TestSinkResizesRows(t *testing.T) {
+	var s strings.Builder
+
+	sk := NewSink(&s, SinkCSV, NewSchema(NewColumn(), NewColumn()))
+	sk.WriteRow(NewRow(WithRowData(1, 2, 3)))
+	sk.WriteRow(NewRow(WithRowData(1)))
+	sk.Flush()
+	assert.Equal(t, "1,2\n1,\n", s.String())
+}
+
+func TestSinkNamedColumns(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewSchema(NewColumn(WithColumnName("name")), NewColumn(WithColumnName("age")))
+	r := NewRow(WithRowSchema(s), WithRowData("alice", 30))
+
+	var csv, ndjson strings.Builder
+	c := NewSink(&csv, SinkCSV, s)
+	j := NewSink(&ndjson, SinkNDJSON, s)
+	for _, sk := range []*Sink{c, j} {
+		assert.NoError(sk.WriteHeader())
+		assert.NoError(sk.WriteRow(r))
+		assert.NoError(sk.Flush())
+	}
+	assert.Equal("name,age\nalice,30\n", csv.String())
+	assert.Equal("{\"name\":\"alice\",\"age\":30}\n", ndjson.String(), "objects in the order of the columns")
+}
@@ -0,0 +1,205 @@
+package pprint
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Implemented by types that decide their own fields in a table, without reflection. Push() and
+// NewNodeFromStructs() use the returned fields instead of the value.
+type RowMarshaler interface {
+	PprintRow() []interface{}
+}
+
+// Builds a node from a slice of structs or pointers to structs, each element becomes a child of
+// the returned node and each exported field a column named by its title. Returns the titles of the
+// columns, e.g. for WithHeader(), and any error encountered. nil pointers become empty rows.
+//
+// Elements implementing RowMarshaler are pushed with the fields they return, the columns are still
+// described by the tags of the struct. Elements of other types implementing it are accepted as well,
+// without titles then.
+//
+// Columns are described by `pprint` tags of the fields, a title followed by options:
+//
+//	Name  string `pprint:"NAME,left,width=20"`
+//	Score float64 `pprint:",verb=%.2f"`
+//	token string // unexported, skipped
+//	Debug bool   `pprint:"-"`
+//
+// The fields of embedded structs are promoted as if they were fields of the outer struct, unless
+// the embedded struct is tagged with a title. An empty title means the field name. Options are:
+//
+// left, center: the alignment, columns are right-aligned by default.
+//
+// width=N, min=N, wrap=N: see WithWidth(), WithMinWidth() and WithWrap().
+//
+// verb=V: see WithVerb().
+func NewNodeFromStructs(slice interface{}) (*Node, []interface{}, error) {
+	rv := reflect.ValueOf(slice)
+	if k := rv.Kind(); k != reflect.Slice && k != reflect.Array {
+		return nil, nil, fmt.Errorf("NewNodeFromStructs: %T isn't a slice", slice)
+	}
+	et := rv.Type().Elem()
+	t := et
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		if !et.Implements(rowMarshalerType) {
+			return nil, nil, fmt.Errorf("NewNodeFromStructs: %s isn't a struct", t)
+		}
+		n := NewNode()
+		for i := 0; i < rv.Len(); i++ {
+			if _, err := n.Push(rv.Index(i).Interface()); err != nil {
+				return nil, nil, err
+			}
+		}
+		return n, nil, nil
+	}
+
+	fields, err := structFields(t)
+	if err != nil {
+		return nil, nil, fmt.Errorf("NewNodeFromStructs: %v", err)
+	}
+	var (
+		cols   = make([]Column, len(fields))
+		titles = make([]interface{}, len(fields))
+	)
+	for i, f := range fields {
+		cols[i], titles[i] = NewColumn(append(f.opts, WithColumnName(f.title))...), f.title
+	}
+
+	n := NewNode(WithColumns(cols...))
+	for i := 0; i < rv.Len(); i++ {
+		e := rv.Index(i)
+		values := []interface{}{e.Interface()}
+		if _, ok := values[0].(RowMarshaler); !ok || (e.Kind() == reflect.Ptr && e.IsNil()) {
+			values = structValues(e, fields)
+		}
+		if _, err := n.Push(values...); err != nil {
+			return nil, nil, err
+		}
+	}
+	return n, titles, nil
+}
+
+// Converts a struct, or a pointer to a struct, into a row of its own schema, with columns described
+// and named by `pprint` tags the same way as NewNodeFromStructs(). Returns any error encountered.
+func NewRowFromStruct(v interface{}) (*Row, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("NewRowFromStruct: %T isn't a struct", v)
+	}
+
+	fields, err := structFields(rv.Type())
+	if err != nil {
+		return nil, fmt.Errorf("NewRowFromStruct: %v", err)
+	}
+	cols := make([]Column, len(fields))
+	for i, f := range fields {
+		cols[i] = NewColumn(append(f.opts, WithColumnName(f.title))...)
+	}
+	return NewRow(WithRowColumns(cols...), WithRowData(structValues(rv, fields)...)), nil
+}
+
+var rowMarshalerType = reflect.TypeOf((*RowMarshaler)(nil)).Elem()
+
+// A field of a struct type mapped to a column.
+type structField struct {
+	// Index sequence for reflect.Value.FieldByIndex().
+	index []int
+
+	title string
+	opts  []ColumnOpt
+}
+
+// Returns the fields of t to map to columns, in order.
+func structFields(t reflect.Type) ([]structField, error) {
+	var out []structField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("pprint")
+		if tag == "-" {
+			continue
+		}
+
+		// Promotes the fields of embedded structs, exported or not.
+		if ft := f.Type; f.Anonymous && tag == "" {
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				promoted, err := structFields(ft)
+				if err != nil {
+					return nil, err
+				}
+				for _, p := range promoted {
+					p.index = append([]int{i}, p.index...)
+					out = append(out, p)
+				}
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+
+		sf := structField{index: []int{i}, title: f.Name}
+		parts := strings.Split(tag, ",")
+		if parts[0] != "" {
+			sf.title = parts[0]
+		}
+		for _, p := range parts[1:] {
+			opt, err := structTagOpt(p)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %v", f.Name, err)
+			}
+			sf.opts = append(sf.opts, opt)
+		}
+		out = append(out, sf)
+	}
+	return out, nil
+}
+
+// Returns the column option of an option of a tag, e.g. "width=20".
+func structTagOpt(p string) (ColumnOpt, error) {
+	key, val, _ := strings.Cut(p, "=")
+	switch key {
+	case "left":
+		return WithLeftAlignment(), nil
+	case "center":
+		return WithCenterAlignment(), nil
+	case "verb":
+		return WithVerb(val), nil
+	case "width", "min", "wrap":
+		w, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("bad %s %q", key, val)
+		}
+		return map[string]func(int) ColumnOpt{"width": WithWidth, "min": WithMinWidth, "wrap": WithWrap}[key](w), nil
+	}
+	return nil, fmt.Errorf("unknown option %q", p)
+}
+
+// Returns the values of fields of the struct v, or nils if v is a nil pointer. Fields of nil
+// embedded structs are nils as well.
+func structValues(v reflect.Value, fields []structField) []interface{} {
+	out := make([]interface{}, len(fields))
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return out
+		}
+		v = v.Elem()
+	}
+	for i, f := range fields {
+		if fv, err := v.FieldByIndexErr(f.index); err == nil {
+			out[i] = fv.Interface()
+		}
+	}
+	return out
+}
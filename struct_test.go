@@ -0,0 +1,116 @@
+package pprint
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewNodeFromStructs(t *testing.T) {
+	assert := assert.New(t)
+
+	type user struct {
+		Name  string  `pprint:"NAME,left"`
+		Age   int     `pprint:",width=4"`
+		Score float64 `pprint:"SCORE,verb=%.1f,center"`
+		Debug bool    `pprint:"-"`
+		token string
+	}
+
+	n, header, err := NewNodeFromStructs([]user{
+		{"alice", 30, 9.25, true, "x"},
+		{"bob", 4, 10, false, "y"},
+	})
+	assert.NoError(err)
+	assert.Equal([]interface{}{"NAME", "Age", "SCORE"}, header)
+	assert.Equal("alice   30 9.2 \nbob      4 10.0\n", n.String())
+	assert.Equal(30, n.ChildAt(0).Row().fields[1], "raw values")
+
+	p, _, err := NewNodeFromStructs([]*user{{Name: "carol"}, nil})
+	assert.NoError(err)
+	assert.Equal("carol    0 0.0\n              \n", p.String(), "pointers")
+
+	e, _, err := NewNodeFromStructs([]user{})
+	assert.NoError(err)
+	assert.Equal(3, e.Schema().count, "columns without rows")
+
+	_, _, err = NewNodeFromStructs(user{})
+	assert.EqualError(err, "NewNodeFromStructs: pprint.user isn't a slice")
+	_, _, err = NewNodeFromStructs([]int{1})
+	assert.EqualError(err, "NewNodeFromStructs: int isn't a struct")
+	_, _, err = NewNodeFromStructs([]struct {
+		A int `pprint:",width=x"`
+	}{})
+	assert.EqualError(err, `NewNodeFromStructs: field A: bad width "x"`)
+	_, _, err = NewNodeFromStructs([]struct {
+		A int `pprint:",bold"`
+	}{})
+	assert.EqualError(err, `NewNodeFromStructs: field A: unknown option "bold"`)
+}
+
+func TestNewRowFromStruct(t *testing.T) {
+	assert := assert.New(t)
+
+	type Base struct {
+		ID   int    `pprint:"ID"`
+		Note string `pprint:"-"`
+	}
+	type meta struct {
+		Owner string `pprint:",left"`
+	}
+	type item struct {
+		Base
+		*meta
+		Name  string
+		Inner Base `pprint:"INNER"`
+	}
+
+	r, err := NewRowFromStruct(item{Base: Base{ID: 7, Note: "x"}, meta: &meta{"ann"}, Name: "box", Inner: Base{ID: 1}})
+	assert.NoError(err)
+	assert.Equal([]interface{}{7, "ann", "box", Base{ID: 1}}, r.fields, "promoted fields")
+	assert.Equal("%-3s", r.schema.cols[1].String())
+
+	r, err = NewRowFromStruct(&item{Name: "nil meta"})
+	assert.NoError(err)
+	assert.Equal([]interface{}{0, nil, "nil meta", Base{}}, r.fields)
+
+	_, err = NewRowFromStruct(1)
+	assert.EqualError(err, "NewRowFromStruct: int isn't a struct")
+	_, err = NewRowFromStruct((*item)(nil))
+	assert.EqualError(err, "NewRowFromStruct: *pprint.item isn't a struct")
+}
+
+type money struct {
+	Amount   int    `pprint:"AMOUNT"`
+	Currency string `pprint:"CUR,left"`
+}
+
+func (m money) PprintRow() []interface{} {
+	return []interface{}{fmt.Sprintf("%d.%02d", m.Amount/100, m.Amount%100), m.Currency}
+}
+
+type pair [2]int
+
+func (p pair) PprintRow() []interface{} {
+	return []interface{}{p[0], p[1], p[0] + p[1]}
+}
+
+func TestRowMarshaler(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode()
+	n.Push(money{1250, "EUR"})
+	n.Push(money{5, "USD"}, "extra")
+	assert.Equal("  12.50   EUR\n{5 USD} extra\n", n.String(), "only a single input")
+
+	m, header, err := NewNodeFromStructs([]money{{1250, "EUR"}, {5, "USD"}})
+	assert.NoError(err)
+	assert.Equal([]interface{}{"AMOUNT", "CUR"}, header)
+	assert.Equal("12.50 EUR\n 0.05 USD\n", m.String())
+
+	p, header, err := NewNodeFromStructs([]pair{{1, 2}, {10, 20}})
+	assert.NoError(err)
+	assert.Nil(header)
+	assert.Equal(" 1  2  3\n10 20 30\n", p.String(), "other types")
+}
@@ -0,0 +1,95 @@
+package pprint
+
+import "strings"
+
+// ANSI SGR parameters, e.g. "31" for red foreground, "1;4" for bold and underline.
+// The zero value means no style.
+type Style string
+
+const (
+	StyleBold      Style = "1"
+	StyleDim       Style = "2"
+	StyleUnderline Style = "4"
+	StyleReverse   Style = "7"
+	StyleRed       Style = "31"
+	StyleGreen     Style = "32"
+	StyleYellow    Style = "33"
+	StyleBlue      Style = "34"
+)
+
+// Combines styles, e.g. StyleBold.With(StyleRed).
+func (s Style) With(o Style) Style {
+	switch {
+	case s == "":
+		return o
+	case o == "":
+		return s
+	}
+	return s + ";" + o
+}
+
+// Wraps text in the escape sequences of the style, an empty text stays empty.
+func (s Style) apply(text string) string {
+	if s == "" || text == "" {
+		return text
+	}
+	return "\x1b[" + string(s) + "m" + text + "\x1b[0m"
+}
+
+// Wraps a line in the escape sequences of the style. Styles are restored after resets inside the
+// line, so that the style spans across styled cells.
+func (s Style) applyLine(line string) string {
+	if s == "" || line == "" {
+		return line
+	}
+	on := "\x1b[" + string(s) + "m"
+	return on + strings.ReplaceAll(line, "\x1b[0m", "\x1b[0m"+on) + "\x1b[0m"
+}
+
+// Decides the style of a cell by its column index, the index of its row among the printed rows and
+// its raw value. Returns the zero Style to leave the cell as is.
+type CellStyler func(col, rowIdx int, v interface{}) Style
+
+// Style individual cells by their values, e.g. red for negative numbers. Styles are applied after
+// width calculation, so they never affect alignment. Ignored by WithPlain().
+func WithCellStyler(fn CellStyler) PrintingOpt {
+	return func(p *Printing) {
+		p.cellStyler = fn
+	}
+}
+
+// Style every other printed row, starting from the second one, to improve scanability of wide
+// tables, e.g. WithZebra(StyleReverse) or WithZebra("48;5;236") for a dark gray background.
+// The header and rows rendered by a RowRenderer aren't striped. Ignored by WithPlain().
+func WithZebra(s Style) PrintingOpt {
+	return func(p *Printing) {
+		p.zebra = s
+	}
+}
+
+// Decides the style of a row by the node holding it and the index of the row among the printed rows,
+// e.g. by the metadata set by Node.Set(). Returns the zero Style to leave the row as is.
+type RowStyler func(n *Node, rowIdx int) Style
+
+// Style whole rows by their nodes, e.g. red for nodes marked as errors:
+//   WithRowStyler(func(n *Node, _ int) Style {
+//     if n.Get("error") != nil {
+//       return StyleRed
+//     }
+//     return ""
+//   })
+// Row styles combine with WithZebra() and WithCellStyler(). Ignored by WithPlain(), and by rows
+// printed with RunRow() or rendered by a RowRenderer.
+func WithRowStyler(fn RowStyler) PrintingOpt {
+	return func(p *Printing) {
+		p.rowStyler = fn
+	}
+}
+
+// Style cells holding errors, e.g. WithErrorStyle(StyleRed), so that failures stand out of the
+// table. Cells styled by a CellStyler keep their style. Ignored by WithPlain().
+func WithErrorStyle(s Style) PrintingOpt {
+	return func(p *Printing) {
+		p.errorStyle = s
+	}
+}
@@ -0,0 +1,144 @@
+package pprint
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStyle(t *testing.T) {
+	assert.Equal(t, Style("1;31"), StyleBold.With(StyleRed))
+	assert.Equal(t, StyleRed, Style("").With(StyleRed))
+	assert.Equal(t, StyleRed, StyleRed.With(""))
+	assert.Equal(t, "\x1b[31mx\x1b[0m", StyleRed.apply("x"))
+	assert.Equal(t, "", StyleRed.apply(""))
+	assert.Equal(t, "x", Style("").apply("x"))
+}
+
+func TestPrintingWithCellStyler(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		negatives = func(col, rowIdx int, v interface{}) Style {
+			if i, ok := v.(int); ok && i < 0 {
+				return StyleRed
+			}
+			if rowIdx == 0 && col == 0 {
+				return StyleBold
+			}
+			return ""
+		}
+	)
+
+	n := NewNode()
+	n.Push("a", -1)
+	n.Push("bb", 10)
+
+	{
+		var s strings.Builder
+		Print(n, WithWriter(&s), WithHeader("N", "V"), WithCellStyler(negatives))
+		assert.Equal(
+			" N  V\n"+
+				" \x1b[1ma\x1b[0m \x1b[31m-1\x1b[0m\n"+
+				"bb 10\n",
+			s.String(),
+			"headers aren't styled, padding is outside of styles",
+		)
+	}
+	{
+		var s strings.Builder
+		Print(n, WithWriter(&s), WithCellStyler(negatives), WithPlain())
+		assert.Equal(" a -1\nbb 10\n", s.String())
+	}
+}
+
+func TestPrintingWithZebra(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		s strings.Builder
+	)
+
+	n := NewNode()
+	n.Push("a", -1)
+	n.Push("bb", -2)
+	n.Push("c", 3)
+
+	Print(n, WithWriter(&s), WithZebra(StyleReverse), WithCellStyler(func(col, rowIdx int, v interface{}) Style {
+		if i, ok := v.(int); ok && i < 0 {
+			return StyleRed
+		}
+		return ""
+	}))
+	assert.Equal(
+		" a \x1b[31m-1\x1b[0m\n"+
+			"\x1b[7mbb \x1b[31m-2\x1b[0m\x1b[7m\x1b[0m\n"+
+			" c  3\n",
+		s.String(),
+	)
+
+	s.Reset()
+	w := NewNode(WithColumns(NewColumn(WithWrap(2))))
+	w.Push("a")
+	w.Push("bbcc")
+	Print(w, WithWriter(&s), WithZebra(StyleReverse))
+	assert.Equal(" a\n\x1b[7mbb\x1b[0m\n\x1b[7mcc\x1b[0m\n", s.String(), "once per row")
+}
+
+func TestPrintingWithRowStyler(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		s strings.Builder
+	)
+
+	n := NewNode()
+	n.Push("a", 1)
+	m, _ := n.Push("b", 2)
+	m.Set("error", true)
+	n.Push("c", 3)
+
+	styler := func(n *Node, rowIdx int) Style {
+		if n.Get("error") != nil {
+			return StyleRed
+		}
+		return ""
+	}
+	Print(n, WithWriter(&s), WithRowStyler(styler), WithZebra(StyleReverse))
+	assert.Equal(
+		"a 1\n"+
+			"\x1b[7;31mb 2\x1b[0m\n"+
+			"c 3\n",
+		s.String(),
+	)
+
+	s.Reset()
+	Print(n, WithWriter(&s), WithRowStyler(styler), WithPlain())
+	assert.Equal("a 1\nb 2\nc 3\n", s.String())
+}
+
+func TestPrintingWithErrorStyle(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		s strings.Builder
+	)
+
+	n := NewNode()
+	n.Push("a", errors.New("failed"))
+	n.Push("b", nil)
+
+	Print(n, WithWriter(&s), WithErrorStyle(StyleRed))
+	assert.Equal("a \x1b[31mfailed\x1b[0m\nb       \n", s.String())
+
+	s.Reset()
+	Print(n, WithWriter(&s), WithErrorStyle(StyleRed), WithCellStyler(func(col, rowIdx int, v interface{}) Style {
+		if col == 1 {
+			return StyleBold
+		}
+		return ""
+	}))
+	assert.Equal("a \x1b[1mfailed\x1b[0m\nb       \n", s.String(), "cell stylers first")
+}
@@ -0,0 +1,82 @@
+package pprint
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Aggregates the raw values of a column into a single value, e.g. a total.
+type AggFn func(values []interface{}) interface{}
+
+// Counts the values that aren't nil.
+func AggCount(values []interface{}) interface{} {
+	n := 0
+	for _, v := range values {
+		if v != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// Sums up the numeric values, others are ignored. Returns an int if all of them are integers,
+// a float64 otherwise.
+func AggSum(values []interface{}) interface{} {
+	var (
+		ints   int64
+		floats float64
+		isInt  = true
+	)
+	for _, v := range values {
+		if v == nil {
+			continue
+		}
+		switch rv := reflect.ValueOf(v); rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			ints += rv.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			ints += int64(rv.Uint())
+		case reflect.Float32, reflect.Float64:
+			floats += rv.Float()
+			isInt = false
+		}
+	}
+	if isInt {
+		return int(ints)
+	}
+	return float64(ints) + floats
+}
+
+// Aggregates column col of receiver's descendants per depth, e.g. totals per directory level,
+// by agg. Returns a node of (depth, aggregate) rows that prints as a compact table, where the
+// children of receiver are at depth 1, and any error encountered.
+//
+// Descendants whose schemas don't have column col are skipped.
+func (n *Node) LevelSummary(col int, agg AggFn) (*Node, error) {
+	if n.schema == nil || col < 0 || col >= n.schema.count {
+		return nil, fmt.Errorf("LevelSummary: column %d doesn't exist", col)
+	}
+
+	var levels [][]interface{}
+	var collect func(c *Node, depth int)
+	collect = func(c *Node, depth int) {
+		if len(levels) < depth {
+			levels = append(levels, []interface{}{})
+		}
+		if r := c.Row(); r != nil && col < r.schema.count {
+			levels[depth-1] = append(levels[depth-1], r.fields[col])
+		}
+		for _, cc := range c.nodes {
+			collect(cc, depth+1)
+		}
+	}
+	n.EachNode(func(c *Node) {
+		collect(c, 1)
+	})
+
+	out := NewNode()
+	for i, values := range levels {
+		out.Push(i+1, agg(values))
+	}
+	return out, nil
+}
@@ -0,0 +1,45 @@
+package pprint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggFns(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(3, AggCount([]interface{}{1, "a", nil, 2.5}))
+	assert.Equal(0, AggCount(nil))
+	assert.Equal(6, AggSum([]interface{}{1, int64(2), uint8(3), "x", nil}))
+	assert.Equal(4.5, AggSum([]interface{}{1, 2.5, float32(1)}))
+	assert.Equal(0, AggSum(nil))
+}
+
+func TestNodeLevelSummary(t *testing.T) {
+	assert := assert.New(t)
+
+	root := NewNode()
+	usr, _ := root.Push("usr", 10)
+	usr.Push("bin", 3)
+	lib, _ := usr.Push("lib", 4)
+	lib.Push("libc.so", 2)
+	root.Push("etc", 5)
+
+	sub := NewNode(WithColumns(NewColumn()))
+	sub.Push("no sizes")
+	usr.PushNode(sub)
+
+	sum, err := root.LevelSummary(1, AggSum)
+	assert.NoError(err)
+	assert.Equal("1 15\n2  7\n3  2\n", sum.String())
+
+	count, err := root.LevelSummary(0, AggCount)
+	assert.NoError(err)
+	assert.Equal("1 2\n2 2\n3 2\n", count.String(), "rows of other schemas are counted if they have the column")
+
+	_, err = root.LevelSummary(2, AggSum)
+	assert.Error(err)
+	_, err = NewNode().LevelSummary(0, AggSum)
+	assert.Error(err)
+}
@@ -0,0 +1,203 @@
+package pprint
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Widths of runes that replace the ones of runeWidth(), see WithRuneWidthOverride(). The text
+// helpers measure with it, a nil one measures as runeWidth() does.
+type runeWidths map[rune]int
+
+// Returns the width of r, overridden or as runeWidth() says.
+func (m runeWidths) of(r rune) int {
+	if w, ok := m[r]; ok {
+		return w
+	}
+	return runeWidth(r)
+}
+
+// Returns the width of s when printed in a terminal. ANSI escape sequences take no space.
+func (m runeWidths) strWidth(s string) int {
+	w := 0
+	for _, r := range stripANSI(s) {
+		w += m.of(r)
+	}
+	return w
+}
+
+// Returns the number of terminal cells r takes, wcwidth-style: 0 for control characters and
+// combining marks, 2 for East Asian wide and fullwidth characters and emoji, 1 for the others.
+func runeWidth(r rune) int {
+	switch {
+	case r < 0x20 || (r >= 0x7f && r < 0xa0):
+		return 0
+	case r < 0x300:
+		// Fast path for Latin
+		return 1
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	case inRanges(r, wideRanges):
+		return 2
+	}
+	return 1
+}
+
+// East Asian wide (W) and fullwidth (F) characters, including emoji presentation, in order.
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F}, {0x231A, 0x231B}, {0x2329, 0x232A}, {0x23E9, 0x23EC}, {0x23F0, 0x23F0},
+	{0x23F3, 0x23F3}, {0x25FD, 0x25FE}, {0x2614, 0x2615}, {0x2648, 0x2653}, {0x267F, 0x267F},
+	{0x2693, 0x2693}, {0x26A1, 0x26A1}, {0x26AA, 0x26AB}, {0x26BD, 0x26BE}, {0x26C4, 0x26C5},
+	{0x26CE, 0x26CE}, {0x26D4, 0x26D4}, {0x26EA, 0x26EA}, {0x26F2, 0x26F3}, {0x26F5, 0x26F5},
+	{0x26FA, 0x26FA}, {0x26FD, 0x26FD}, {0x2705, 0x2705}, {0x270A, 0x270B}, {0x2728, 0x2728},
+	{0x274C, 0x274C}, {0x274E, 0x274E}, {0x2753, 0x2755}, {0x2757, 0x2757}, {0x2795, 0x2797},
+	{0x27B0, 0x27B0}, {0x27BF, 0x27BF}, {0x2B1B, 0x2B1C}, {0x2B50, 0x2B50}, {0x2B55, 0x2B55},
+	{0x2E80, 0x303E}, {0x3041, 0x33FF}, {0x3400, 0x4DBF}, {0x4E00, 0x9FFF}, {0xA000, 0xA4CF},
+	{0xA960, 0xA97F}, {0xAC00, 0xD7A3}, {0xF900, 0xFAFF}, {0xFE10, 0xFE19}, {0xFE30, 0xFE6F},
+	{0xFF00, 0xFF60}, {0xFFE0, 0xFFE6}, {0x16FE0, 0x16FE4}, {0x17000, 0x18CFF}, {0x1B000, 0x1B2FF},
+	{0x1F004, 0x1F004}, {0x1F0CF, 0x1F0CF}, {0x1F18E, 0x1F18E}, {0x1F191, 0x1F19A},
+	{0x1F200, 0x1F251}, {0x1F300, 0x1F320}, {0x1F32D, 0x1F335}, {0x1F337, 0x1F37C},
+	{0x1F37E, 0x1F393}, {0x1F3A0, 0x1F3CA}, {0x1F3CF, 0x1F3D3}, {0x1F3E0, 0x1F3F0},
+	{0x1F3F4, 0x1F3F4}, {0x1F3F8, 0x1F43E}, {0x1F440, 0x1F440}, {0x1F442, 0x1F4FC},
+	{0x1F4FF, 0x1F53D}, {0x1F54B, 0x1F54E}, {0x1F550, 0x1F567}, {0x1F57A, 0x1F57A},
+	{0x1F595, 0x1F596}, {0x1F5A4, 0x1F5A4}, {0x1F5FB, 0x1F64F}, {0x1F680, 0x1F6C5},
+	{0x1F6CC, 0x1F6CC}, {0x1F6D0, 0x1F6D2}, {0x1F6D5, 0x1F6D7}, {0x1F6EB, 0x1F6EC},
+	{0x1F6F4, 0x1F6FC}, {0x1F7E0, 0x1F7EB}, {0x1F90C, 0x1F93A}, {0x1F93C, 0x1F945},
+	{0x1F947, 0x1F9FF}, {0x1FA70, 0x1FAFF}, {0x20000, 0x2FFFD}, {0x30000, 0x3FFFD},
+}
+
+// Reports whether r is in one of the ordered ranges.
+func inRanges(r rune, ranges [][2]rune) bool {
+	i := sort.Search(len(ranges), func(i int) bool { return ranges[i][1] >= r })
+	return i < len(ranges) && ranges[i][0] <= r
+}
+
+// Matches ANSI CSI sequences (colors, cursor movements) and OSC sequences (titles, hyperlinks).
+var ansiRe = regexp.MustCompile("\x1b\\[[0-9;?]*[ -/]*[@-~]|\x1b\\][^\x07\x1b]*(?:\x07|\x1b\\\\)")
+
+// Returns s as an OSC 8 hyperlink to u, or as is if u is empty.
+func hyperlink(u, s string) string {
+	if u == "" || s == "" {
+		return s
+	}
+	return "\x1b]8;;" + u + "\x1b\\" + s + "\x1b]8;;\x1b\\"
+}
+
+// Removes ANSI escape sequences from s.
+func stripANSI(s string) string {
+	if !strings.ContainsRune(s, '\x1b') {
+		return s
+	}
+	return ansiRe.ReplaceAllString(s, "")
+}
+
+// Breaks s into lines no wider than width at spaces. Words wider than width are broken at rune
+// boundaries. Returns s as is if it fits or width <= 0.
+func (m runeWidths) wrapText(s string, width int) []string {
+	if width <= 0 || m.strWidth(s) <= width {
+		return []string{s}
+	}
+
+	var (
+		lines []string
+		line  string
+	)
+	for _, word := range strings.Fields(s) {
+		for m.strWidth(word) > width {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			var head string
+			head, word = m.cutWidth(word, width)
+			lines = append(lines, head)
+		}
+
+		switch {
+		case word == "":
+		case line == "":
+			line = word
+		case m.strWidth(line)+1+m.strWidth(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// Splits s at a rune boundary so that head is no wider than width. head has at least one rune if
+// s isn't empty, even if that rune alone is wider than width. ANSI escape sequences are never split,
+// the ones right after the cut stay with head, so that resets close the styles of head.
+func (m runeWidths) cutWidth(s string, width int) (head, tail string) {
+	var (
+		escapes = ansiRe.FindAllStringIndex(s, -1)
+		i, w    int
+		runes   int
+	)
+	for i < len(s) {
+		if len(escapes) > 0 && escapes[0][0] == i {
+			i = escapes[0][1]
+			escapes = escapes[1:]
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		rw := m.strWidth(string(r))
+		if runes > 0 && w+rw > width {
+			break
+		}
+		i, w, runes = i+size, w+rw, runes+1
+	}
+	return s[:i], s[i:]
+}
+
+// Returns the head of s no wider than width. Styles left open by the cut are reset.
+func (m runeWidths) truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	head, tail := m.cutWidth(s, width)
+	if m.strWidth(head) > width {
+		head = ""
+	}
+	if tail != "" && strings.ContainsRune(s, '\x1b') {
+		head += "\x1b[0m"
+	}
+	return head
+}
+
+// Splits s at newlines, "\r\n" included.
+func splitLines(s string) []string {
+	return strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+}
+
+// Returns the width of the widest line of s.
+func (m runeWidths) maxLineWidth(s string) int {
+	if !strings.ContainsRune(s, '\n') {
+		return m.strWidth(s)
+	}
+	w := 0
+	for _, l := range splitLines(s) {
+		if lw := m.strWidth(l); lw > w {
+			w = lw
+		}
+	}
+	return w
+}
+
+var newlineEscaper = strings.NewReplacer("\r\n", `\r\n`, "\n", `\n`)
+
+// Replaces newlines in s with their escape sequences.
+func escapeNewlines(s string) string {
+	if !strings.ContainsRune(s, '\n') {
+		return s
+	}
+	return newlineEscaper.Replace(s)
+}
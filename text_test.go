@@ -0,0 +1,110 @@
+package pprint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapText(t *testing.T) {
+	tests := map[string]struct {
+		in       string
+		width    int
+		expected []string
+	}{
+		"fits":             {"hello world", 11, []string{"hello world"}},
+		"no width":         {"hello world", 0, []string{"hello world"}},
+		"empty":            {"", 3, []string{""}},
+		"at spaces":        {"the quick brown fox", 10, []string{"the quick", "brown fox"}},
+		"spaces collapsed": {"a  b   c", 3, []string{"a b", "c"}},
+		"long word":        {"abcdefgh ij", 3, []string{"abc", "def", "gh", "ij"}},
+		"long word exact":  {"x abcdef", 3, []string{"x", "abc", "def"}},
+		"rune boundaries":  {"héllo", 2, []string{"hé", "ll", "o"}},
+		"wide runes":       {"日本語 ok", 4, []string{"日本", "語", "ok"}},
+	}
+	for name, test := range tests {
+		assert.Equal(t, test.expected, runeWidths(nil).wrapText(test.in, test.width), name)
+	}
+}
+
+func TestCutWidth(t *testing.T) {
+	head, tail := runeWidths(nil).cutWidth("日本", 1)
+	assert.Equal(t, "日", head, "at least one rune")
+	assert.Equal(t, "本", tail)
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "ab", runeWidths(nil).truncate("abc", 2))
+	assert.Equal(t, "abc", runeWidths(nil).truncate("abc", 5))
+	assert.Equal(t, "", runeWidths(nil).truncate("abc", 0))
+}
+
+func TestCutWidthWithANSI(t *testing.T) {
+	tests := map[string]struct {
+		in         string
+		width      int
+		head, tail string
+	}{
+		"escape kept whole": {"\x1b[31mabc\x1b[0m", 2, "\x1b[31mab", "c\x1b[0m"},
+		"trailing reset":    {"\x1b[31mab\x1b[0mc", 2, "\x1b[31mab\x1b[0m", "c"},
+		"hyperlink":         {"\x1b]8;;x\x1b\\ab\x1b]8;;\x1b\\", 1, "\x1b]8;;x\x1b\\a", "b\x1b]8;;\x1b\\"},
+	}
+	for name, test := range tests {
+		head, tail := runeWidths(nil).cutWidth(test.in, test.width)
+		assert.Equal(t, test.head, head, name)
+		assert.Equal(t, test.tail, tail, name)
+	}
+
+	assert.Equal(t, 3, runeWidths(nil).strWidth("\x1b[1;31mabc\x1b[0m"))
+	assert.Equal(t, []string{"\x1b[31mab", "cd\x1b[0m"}, runeWidths(nil).wrapText("\x1b[31mab cd\x1b[0m", 2))
+}
+
+func TestStrWidth(t *testing.T) {
+	tests := map[string]struct {
+		in    string
+		width int
+	}{
+		"ascii":           {"hello", 5},
+		"latin":           {"héllo", 5},
+		"combining mark":  {"he\u0301llo", 5},
+		"cjk":             {"日本語", 6},
+		"hangul":          {"한국어", 6},
+		"fullwidth":       {"ＡＢ", 4},
+		"emoji":           {"🍣🚀", 4},
+		"emoji + vs16":    {"❤\ufe0f", 1},
+		"zero width join": {"a\u200db", 2},
+		"control":         {"a\tb", 2},
+		"ansi + cjk":      {"\x1b[31m日本\x1b[0m", 4},
+	}
+	for name, test := range tests {
+		assert.Equal(t, test.width, runeWidths(nil).strWidth(test.in), name)
+	}
+}
+
+func TestWithRuneWidthOverride(t *testing.T) {
+	assert := assert.New(t)
+
+	m := map[rune]int{'': 2, '🚀': 1, 'x': -1}
+	opt := WithRuneWidthOverride(m)
+	m['a'] = 5
+
+	p := NewPrinting(opt)
+	assert.Equal(3, p.runeWidths.strWidth("a"), "powerline glyph")
+	assert.Equal(2, p.runeWidths.strWidth("🚀a"), "emoji")
+	assert.Equal(0, p.runeWidths.strWidth("x"), "negative widths")
+
+	n := NewNode()
+	n.Push("🚀", 1)
+	n.Push("ab", 2)
+	assert.Equal("🚀 1\nab 2\n", n.String(), "measured as wide")
+
+	var s strings.Builder
+	Print(n, WithWriter(&s), opt)
+	assert.Equal(" 🚀 1\nab 2\n", s.String(), "measured again by the printing")
+	assert.Equal("🚀 1\nab 2\n", n.String(), "schemas are left alone")
+
+	s.Reset()
+	NewPrinting(WithWriter(&s), opt, WithColSep("|")).RunRow(n.ChildAt(0).Row())
+	assert.Equal("🚀|1\n", s.String(), "single rows")
+}
@@ -0,0 +1,234 @@
+package pprint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// A serializable set of printing options and sorting, so that "views" of a tree can be saved in
+// config files and picked by name, e.g. by a --view flag. Options that can't be serialized, such as
+// writers, hooks and stylers, aren't part of a view and have to be added when printing.
+type View struct {
+	ColSep  *string      `json:"colSep,omitempty"`
+	LineBrk *string      `json:"lineBrk,omitempty"`
+	Plain   bool         `json:"plain,omitempty"`
+	Border  *BorderStyle `json:"border,omitempty"`
+	Header  *[]string    `json:"header,omitempty"`
+	Footer  []string     `json:"footer,omitempty"`
+	Columns *ViewColumns `json:"columns,omitempty"`
+
+	Title         string      `json:"title,omitempty"`
+	TitleCentered bool        `json:"titleCentered,omitempty"`
+	FitWidth      int         `json:"fitWidth,omitempty"`
+	Zebra         Style       `json:"zebra,omitempty"`
+	ErrorStyle    Style       `json:"errorStyle,omitempty"`
+	Newlines      NewlineMode `json:"newlines,omitempty"`
+
+	RowNumbers     bool `json:"rowNumbers,omitempty"`
+	GroupSeparator bool `json:"groupSeparator,omitempty"`
+	MaxDepth       int  `json:"maxDepth,omitempty"`
+	DepthMarker    bool `json:"depthMarker,omitempty"`
+	LeavesOnly     bool `json:"leavesOnly,omitempty"`
+	SkipReceiver   bool `json:"skipReceiver,omitempty"`
+
+	// Applied in order by Sort(), so the last one is the primary order.
+	SortBy []ViewSort `json:"sortBy,omitempty"`
+}
+
+// Indexes or names of the columns a view prints, see WithVisibleColumns(), e.g. [0, "AGE"]. Views
+// hold a pointer to it, so that no column, [], is kept apart from all of them, a missing one.
+type ViewColumns []interface{}
+
+// Decodes indexes as ints rather than float64. Returns an error for values other than integers and
+// strings.
+func (c *ViewColumns) UnmarshalJSON(b []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var in []interface{}
+	if err := dec.Decode(&in); err != nil {
+		return err
+	}
+	out := make(ViewColumns, len(in))
+	for i, v := range in {
+		switch v := v.(type) {
+		case string:
+			out[i] = v
+		case json.Number:
+			k, err := strconv.Atoi(string(v))
+			if err != nil {
+				return fmt.Errorf("column %s isn't an index", v)
+			}
+			out[i] = k
+		default:
+			return fmt.Errorf("column %v isn't an index or a name", v)
+		}
+	}
+	*c = out
+	return nil
+}
+
+// A Node.Sort() call of a view.
+type ViewSort struct {
+	Column     int  `json:"column"`
+	Descending bool `json:"descending,omitempty"`
+	Recursive  bool `json:"recursive,omitempty"`
+}
+
+// Returns the serializable options of p as a view.
+func (p *Printing) View() View {
+	v := View{
+		ColSep:   &p.colSep,
+		LineBrk:  &p.lineBrk,
+		Plain:    p.plain,
+		Border:   p.border,
+		FitWidth: p.fitWidth,
+		Zebra:    p.zebra,
+		Newlines: p.newlines,
+
+		ErrorStyle: p.errorStyle,
+
+		RowNumbers:     p.rowNumbers,
+		GroupSeparator: p.groupSep,
+		MaxDepth:       p.maxDepth,
+		DepthMarker:    p.depthMarker,
+		LeavesOnly:     p.leavesOnly,
+		SkipReceiver:   p.skipReceiver,
+	}
+	v.Title, v.TitleCentered = p.title, p.titleCentered
+	if p.header != nil {
+		// An empty header prints the column names, so it's kept apart from no header.
+		h := toStrings(p.header)
+		v.Header = &h
+	}
+	v.Footer = toStrings(p.footer)
+	if p.columns != nil {
+		c := append(ViewColumns{}, p.columns...)
+		v.Columns = &c
+	}
+	return v
+}
+
+// Returns the printing options of the view.
+func (v View) PrintingOpts() []PrintingOpt {
+	var opts []PrintingOpt
+	if v.ColSep != nil {
+		opts = append(opts, WithColSep(*v.ColSep))
+	}
+	if v.LineBrk != nil {
+		opts = append(opts, WithLineBrk(*v.LineBrk))
+	}
+	if v.Plain {
+		opts = append(opts, WithPlain())
+	}
+	if v.Border != nil {
+		opts = append(opts, WithBorderStyle(*v.Border))
+	}
+	if v.Header != nil {
+		opts = append(opts, WithHeader(toInterfaces(*v.Header)...))
+	}
+	switch {
+	case v.Title == "":
+	case v.TitleCentered:
+		opts = append(opts, WithCenteredTitle(v.Title))
+	default:
+		opts = append(opts, WithTitle(v.Title))
+	}
+	if v.Footer != nil {
+		opts = append(opts, WithFooter(toInterfaces(v.Footer)...))
+	}
+	if v.Columns != nil {
+		opts = append(opts, WithVisibleColumns(*v.Columns...))
+	}
+	if v.FitWidth > 0 {
+		opts = append(opts, WithFitWidth(v.FitWidth))
+	}
+	if v.Zebra != "" {
+		opts = append(opts, WithZebra(v.Zebra))
+	}
+	if v.ErrorStyle != "" {
+		opts = append(opts, WithErrorStyle(v.ErrorStyle))
+	}
+	if v.Newlines != NewlinesRaw {
+		opts = append(opts, WithNewlines(v.Newlines))
+	}
+	if v.RowNumbers {
+		opts = append(opts, WithRowNumbers())
+	}
+	if v.GroupSeparator {
+		opts = append(opts, WithGroupSeparator())
+	}
+	if v.MaxDepth > 0 {
+		opts = append(opts, WithMaxDepth(v.MaxDepth))
+	}
+	if v.DepthMarker {
+		opts = append(opts, WithDepthMarker())
+	}
+	if v.LeavesOnly {
+		opts = append(opts, WithLeavesOnly())
+	}
+	if v.SkipReceiver {
+		opts = append(opts, WithSkipReceiverRow())
+	}
+	return opts
+}
+
+// Sorts n as the view says. Returns any error encountered.
+func (v View) Sort(n *Node) error {
+	for _, s := range v.SortBy {
+		var opts []SortOpt
+		if s.Descending {
+			opts = append(opts, WithDescending())
+		}
+		if s.Recursive {
+			opts = append(opts, WithRecursive())
+		}
+
+		if err := n.Sort(s.Column, opts...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sorts and prints n as the view says, opts are appended to the options of the view, e.g. to set
+// the writer. Returns any error encountered.
+func (v View) Print(n *Node, opts ...PrintingOpt) error {
+	if err := v.Sort(n); err != nil {
+		return err
+	}
+	Print(n, append(v.PrintingOpts(), opts...)...)
+	return nil
+}
+
+// Reads views keyed by their names from a JSON object. Returns any error encountered.
+func ReadViews(r io.Reader) (map[string]View, error) {
+	views := map[string]View{}
+	if err := json.NewDecoder(r).Decode(&views); err != nil {
+		return nil, fmt.Errorf("ReadViews: %v", err)
+	}
+	return views, nil
+}
+
+// Returns the string representations of a, nil if a is nil.
+func toStrings(a []interface{}) []string {
+	if a == nil {
+		return nil
+	}
+	s := make([]string, len(a))
+	for i, v := range a {
+		s[i] = MustToString(v)
+	}
+	return s
+}
+
+func toInterfaces(s []string) []interface{} {
+	a := make([]interface{}, len(s))
+	for i, v := range s {
+		a[i] = v
+	}
+	return a
+}
@@ -0,0 +1,125 @@
+package pprint
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestViewExportRestore(t *testing.T) {
+	assert := assert.New(t)
+
+	p := NewPrinting(WithColSep("|"), WithBorderStyle(BorderUnicode), WithHeader("A", "1"), WithFooter("total"), WithVisibleColumns(0, "AGE"), WithCenteredTitle("users"), WithFitWidth(40), WithZebra(StyleDim), WithErrorStyle(StyleRed), WithNewlines(NewlinesSplit), WithRowNumbers(), WithGroupSeparator(), WithMaxDepth(2), WithDepthMarker(), WithLeavesOnly(), WithSkipReceiverRow())
+	b, err := json.Marshal(p.View())
+	assert.NoError(err)
+
+	var v View
+	assert.NoError(json.Unmarshal(b, &v))
+	assert.Equal(p.View(), v)
+
+	restored := NewPrinting(v.PrintingOpts()...)
+	restored.writer, restored.statsHook = p.writer, p.statsHook
+	assert.Equal(p, restored)
+}
+
+func TestViewColumns(t *testing.T) {
+	assert := assert.New(t)
+
+	views, err := ReadViews(strings.NewReader(`{"narrow": {"columns": ["NAME", 2]}}`))
+	assert.NoError(err)
+	assert.Equal(&ViewColumns{"NAME", 2}, views["narrow"].Columns)
+
+	n := NewNode(WithColumns(NewColumn(WithColumnName("NAME")), NewColumn(WithColumnName("EMAIL")), NewColumn(WithColumnName("AGE"))))
+	n.Push("alice", "alice@example.com", 30)
+
+	var s strings.Builder
+	assert.NoError(views["narrow"].Print(n, WithWriter(&s)))
+	assert.Equal("alice 30\n", s.String())
+
+	for _, opts := range [][]PrintingOpt{nil, {WithVisibleColumns()}} {
+		p := NewPrinting(opts...)
+		b, err := json.Marshal(p.View())
+		assert.NoError(err)
+		var v View
+		assert.NoError(json.Unmarshal(b, &v))
+		assert.Equal(p.columns, NewPrinting(v.PrintingOpts()...).columns, "%s", b)
+	}
+
+	_, err = ReadViews(strings.NewReader(`{"narrow": {"columns": [1.5]}}`))
+	assert.EqualError(err, "ReadViews: column 1.5 isn't an index")
+	_, err = ReadViews(strings.NewReader(`{"narrow": {"columns": [true]}}`))
+	assert.EqualError(err, "ReadViews: column true isn't an index or a name")
+}
+
+func TestViewHeader(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NewNode(WithColumns(NewColumn(WithColumnName("NAME")), NewColumn(WithColumnName("AGE"))))
+	n.Push("alice", 30)
+
+	for _, test := range []struct {
+		opts []PrintingOpt
+		want string
+	}{
+		{nil, "alice 30\n"},
+		{[]PrintingOpt{WithHeader()}, " NAME AGE\nalice  30\n"},
+	} {
+		b, err := json.Marshal(NewPrinting(test.opts...).View())
+		assert.NoError(err)
+		var v View
+		assert.NoError(json.Unmarshal(b, &v))
+
+		var s strings.Builder
+		assert.NoError(v.Print(n, WithWriter(&s)))
+		assert.Equal(test.want, s.String(), "%s", b)
+	}
+}
+
+func TestReadViews(t *testing.T) {
+	assert := assert.New(t)
+
+	views, err := ReadViews(strings.NewReader(`{
+		"narrow": {"colSep": ",", "sortBy": [{"column": 1}, {"column": 0, "descending": true, "recursive": true}]},
+		"boxed": {"border": {"Horizontal": "-", "Vertical": "|",
+			"TopLeft": "+", "TopMid": "+", "TopRight": "+",
+			"MidLeft": "+", "Mid": "+", "MidRight": "+",
+			"BottomLeft": "+", "BottomMid": "+", "BottomRight": "+"}, "header": ["N", "V"]}
+	}`))
+	assert.NoError(err)
+
+	newNode := func() *Node {
+		n := NewNode()
+		n.Push(1, "b")
+		m, _ := n.Push(2, "a")
+		n.Push(3, "b")
+		m.Push(4, "x")
+		m.Push(5, "x")
+		return n
+	}
+
+	var s strings.Builder
+	assert.NoError(views["narrow"].Print(newNode(), WithWriter(&s)))
+	assert.Equal("3,b\n2,a\n5,x\n4,x\n1,b\n", s.String())
+
+	s.Reset()
+	assert.NoError(views["boxed"].Print(newNode(), WithWriter(&s)))
+	assert.Equal(
+		"+---+---+\n"+
+			"| N | V |\n"+
+			"+---+---+\n"+
+			"| 1 | b |\n"+
+			"| 2 | a |\n"+
+			"| 4 | x |\n"+
+			"| 5 | x |\n"+
+			"| 3 | b |\n"+
+			"+---+---+\n",
+		s.String(),
+	)
+
+	assert.EqualError(View{SortBy: []ViewSort{{Column: 9}}}.Print(newNode()), "Sort: column 9 doesn't exist")
+
+	_, err = ReadViews(strings.NewReader(`[]`))
+	assert.Error(err)
+}